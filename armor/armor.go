@@ -0,0 +1,207 @@
+// Package armor implements PEM-style armor for base8 data. An armored block
+// looks like:
+//
+//	-----BEGIN Type-----
+//	Key: Value
+//
+//	31467557304605623146755730460562...
+//	-----END Type-----
+//
+// The headers and the blank line that follows them are optional. The body is
+// standard base8, wrapped into lines of 64 characters.
+package armor
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pgavlin/base8"
+)
+
+const (
+	beginPrefix = "-----BEGIN "
+	endPrefix   = "-----END "
+	lineSuffix  = "-----"
+	lineWidth   = 64
+)
+
+// A Block represents an armored block.
+type Block struct {
+	Type   string            // the type, taken from the BEGIN line
+	Header map[string]string // optional headers
+	Body   io.Reader         // the decoded contents of the block
+}
+
+var (
+	// ErrNotFound is returned by Decode when its input contains no BEGIN
+	// line.
+	ErrNotFound = errors.New("armor: no armored block found")
+
+	// ErrMissingEnd is returned by a Block's Body when its input ends
+	// before the block's END line.
+	ErrMissingEnd = errors.New("armor: missing END line")
+
+	// ErrInvalidHeader is returned by Encode when a header key or value
+	// cannot be represented.
+	ErrInvalidHeader = errors.New("armor: invalid header")
+)
+
+type encoder struct {
+	w         io.Writer
+	body      io.WriteCloser
+	blockType string
+}
+
+func (e *encoder) Write(p []byte) (int, error) {
+	return e.body.Write(p)
+}
+
+// Close flushes the body and writes the END line.
+func (e *encoder) Close() error {
+	if err := e.body.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, endPrefix+e.blockType+lineSuffix+"\n")
+	return err
+}
+
+// Encode writes the BEGIN line and headers of an armored block of the given
+// type to w and returns a writer for its body. Data written to the returned
+// writer is encoded as wrapped base8; the caller must Close it to finish the
+// block. Headers are written in sorted order. Header keys must not contain
+// ':' and neither keys nor values may contain line breaks or be empty.
+func Encode(w io.Writer, blockType string, headers map[string]string) (io.WriteCloser, error) {
+	if blockType == "" || strings.ContainsAny(blockType, "\r\n") || strings.HasSuffix(blockType, "-") {
+		return nil, errors.New("armor: invalid block type")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(beginPrefix + blockType + lineSuffix + "\n")
+	keys := make([]string, 0, len(headers))
+	for k, v := range headers {
+		if k == "" || v == "" || strings.ContainsAny(k, ":\r\n") || strings.ContainsAny(v, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		buf.WriteString(k + ": " + headers[k] + "\n")
+	}
+	if len(keys) > 0 {
+		buf.WriteString("\n")
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &encoder{w: w, body: base8.NewWrappingEncoder(w, lineWidth, "\n"), blockType: blockType}, nil
+}
+
+// readLine returns the next line read from r without its line ending or
+// trailing whitespace.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, " \t\r\n"), err
+}
+
+// bodyReader returns the base8 lines of a block's body, without their line
+// endings, until the block's END line.
+type bodyReader struct {
+	r    *bufio.Reader
+	end  string // the block's END line
+	line string // rest of the current line
+	err  error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	for len(b.line) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		line, err := readLine(b.r)
+		switch {
+		case err == io.EOF:
+			b.err = ErrMissingEnd
+		case err != nil:
+			b.err = err
+		case line == b.end:
+			b.err = io.EOF
+		case strings.HasPrefix(line, endPrefix):
+			b.err = errors.New("armor: mismatched END line " + line)
+		default:
+			b.line = line
+		}
+	}
+	n := copy(p, b.line)
+	b.line = b.line[n:]
+	return n, nil
+}
+
+// Decode reads r until it finds a BEGIN line, ignoring any preceding text,
+// and returns the armored block that starts there. The block's headers are
+// parsed immediately; its body is decoded as it is read from Block.Body, up
+// to the block's END line. Decode returns ErrNotFound if r contains no BEGIN
+// line.
+//
+// To decode several blocks from the same input, pass a *bufio.Reader, which
+// Decode reads from directly, and finish reading each block's body before
+// calling Decode again.
+func Decode(r io.Reader) (*Block, error) {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	var blockType string
+	for {
+		line, err := readLine(br)
+		if err == io.EOF {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(line, beginPrefix) && strings.HasSuffix(line, lineSuffix) && len(line) > len(beginPrefix)+len(lineSuffix) {
+			blockType = line[len(beginPrefix) : len(line)-len(lineSuffix)]
+			break
+		}
+	}
+
+	// The headers, if any, are the lines containing a colon that precede
+	// the first blank line.
+	block := &Block{Type: blockType, Header: map[string]string{}}
+	body := &bodyReader{r: br, end: endPrefix + blockType + lineSuffix}
+	for {
+		line, err := readLine(br)
+		if err == io.EOF {
+			return nil, ErrMissingEnd
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			break
+		}
+		if line == body.end {
+			body.err = io.EOF
+			break
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			// The block has no headers, and this is the first body line.
+			body.line = line
+			break
+		}
+		block.Header[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	block.Body = base8.NewDecoder(body)
+	return block, nil
+}
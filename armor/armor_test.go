@@ -0,0 +1,128 @@
+package armor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func encode(t *testing.T, blockType string, headers map[string]string, data []byte) string {
+	t.Helper()
+	var bb bytes.Buffer
+	w, err := Encode(&bb, blockType, headers)
+	if err != nil {
+		t.Fatalf("Encode(%q, %v) = %v", blockType, headers, err)
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	return bb.String()
+}
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		blockType string
+		headers   map[string]string
+		data      string
+		want      string
+	}{
+		{"DATA", nil, "foobar", "-----BEGIN DATA-----\n3146755730460562\n-----END DATA-----\n"},
+		{"DATA", nil, "", "-----BEGIN DATA-----\n-----END DATA-----\n"},
+		{"SECRET KEY", map[string]string{"Version": "1", "Comment": "test"}, "f",
+			"-----BEGIN SECRET KEY-----\nComment: test\nVersion: 1\n\n314=====\n-----END SECRET KEY-----\n"},
+		{"DATA", nil, strings.Repeat("foo", 9),
+			"-----BEGIN DATA-----\n" + strings.Repeat("31467557", 8) + "\n31467557\n-----END DATA-----\n"},
+	}
+	for _, tc := range testCases {
+		got := encode(t, tc.blockType, tc.headers, []byte(tc.data))
+		if got != tc.want {
+			t.Errorf("Encode(%q, %v, %q) = %q, want %q", tc.blockType, tc.headers, tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		blockType string
+		headers   map[string]string
+	}{
+		{"", nil},
+		{"A\nB", nil},
+		{"DATA-", nil},
+		{"DATA", map[string]string{"a:b": "c"}},
+		{"DATA", map[string]string{"a": "b\nc"}},
+		{"DATA", map[string]string{"": "c"}},
+	} {
+		if _, err := Encode(io.Discard, tc.blockType, tc.headers); err == nil {
+			t.Errorf("Encode(%q, %v) succeeded, want error", tc.blockType, tc.headers)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("any + old & data\x00\xff"), 100)
+	for _, headers := range []map[string]string{nil, {"Comment": "hello: world"}} {
+		for _, n := range []int{0, 1, 2, 3, 47, 48, len(data)} {
+			armored := encode(t, "DATA", headers, data[:n])
+			block, err := Decode(strings.NewReader(armored))
+			if err != nil {
+				t.Fatalf("Decode(%q) = %v", armored, err)
+			}
+			got, err := io.ReadAll(block.Body)
+			if err != nil || !bytes.Equal(got, data[:n]) {
+				t.Errorf("Decode(%q).Body = %q, %v; want %q, nil", armored, got, err, data[:n])
+			}
+			if block.Type != "DATA" || !maps.Equal(block.Header, headers) {
+				t.Errorf("Decode(%q) = type %q, headers %v; want %q, %v", armored, block.Type, block.Header, "DATA", headers)
+			}
+		}
+	}
+}
+
+func TestDecodeDocument(t *testing.T) {
+	doc := "Hello,\r\n\r\nHere are the files:\r\n\r\n" +
+		strings.ReplaceAll(encode(t, "A", map[string]string{"Name": "a.txt"}, []byte("foo")), "\n", "\r\n") +
+		"and\n" +
+		encode(t, "B", nil, []byte("bar")) +
+		"Regards\n"
+
+	r := bufio.NewReader(strings.NewReader(doc))
+	for _, want := range []struct{ blockType, body string }{{"A", "foo"}, {"B", "bar"}} {
+		block, err := Decode(r)
+		if err != nil {
+			t.Fatalf("Decode = %v, want block %q", err, want.blockType)
+		}
+		body, err := io.ReadAll(block.Body)
+		if block.Type != want.blockType || string(body) != want.body || err != nil {
+			t.Errorf("Decode = %q, %q, %v; want %q, %q, nil", block.Type, body, err, want.blockType, want.body)
+		}
+	}
+	if _, err := Decode(r); err != ErrNotFound {
+		t.Errorf("Decode at end = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"no block here\n", ErrNotFound},
+		{"-----BEGIN DATA-----\n31467557\n", ErrMissingEnd},
+		{"-----BEGIN DATA-----\nName: x\n", ErrMissingEnd},
+		{"-----BEGIN DATA-----\n31467557\n-----END OTHER-----\n", nil},
+		{"-----BEGIN DATA-----\n3146755!\n-----END DATA-----\n", nil},
+	} {
+		block, err := Decode(strings.NewReader(tc.input))
+		if err == nil {
+			_, err = io.ReadAll(block.Body)
+		}
+		if err == nil || tc.err != nil && err != tc.err {
+			t.Errorf("Decode(%q) = %v, want %v", tc.input, err, tc.err)
+		}
+	}
+}
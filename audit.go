@@ -0,0 +1,57 @@
+package base8
+
+import "strings"
+
+// auditTrailing is the set of characters that AuditPadding may remove from
+// the end of its input.
+const auditTrailing = string(PadChar) + " \t\r\n"
+
+// AuditPadding checks s for over-padding, such as padding appended to an
+// already-padded encoding or trailing whitespace following the padding.
+//
+// If s is already canonical, AuditPadding returns false, s and a nil error.
+// If s is over-padded and the excess can be removed safely, it returns true
+// and the canonical form of s. Only padding and whitespace that follow a
+// complete, correctly padded final quantum are considered excess. Input that
+// falls short of that, such as "314" or "314==", may have been truncated, so
+// padding it out could hide lost data. Neither it nor any other problem,
+// including data following padding, is repaired; AuditPadding returns a
+// CorruptInputError identifying the offending byte or the truncation.
+func AuditPadding(s string) (fixable bool, fixed string, err error) {
+	core := strings.TrimRight(s, auditTrailing)
+	for i := 0; i < len(core); i++ {
+		if core[i]-'0' <= 7 {
+			continue
+		}
+		if core[i] != PadChar {
+			return false, "", StdEncoding.invalidSymbol(i, core[i])
+		}
+		// Find the data that follows the padding.
+		i += strings.IndexFunc(core[i:], func(r rune) bool {
+			return !strings.ContainsRune(auditTrailing, r)
+		})
+		return false, "", invalidPadding(i, core[i])
+	}
+
+	switch len(core) % 8 {
+	case 0:
+		fixed = core
+	case 3:
+		fixed = core + strings.Repeat(string(PadChar), 5)
+	case 6:
+		fixed = core + strings.Repeat(string(PadChar), 2)
+	default:
+		if _, _, err = StdEncoding.decode(make([]byte, DecodedLen(len(s))+3), []byte(s)); err == nil {
+			err = truncated(len(core), ExpectSymbol)
+		}
+		return false, "", err
+	}
+	if !strings.HasPrefix(s, fixed) {
+		// s ends before its final quantum is completely padded.
+		if _, _, err = StdEncoding.decode(make([]byte, DecodedLen(len(s))+3), []byte(s)); err == nil {
+			err = truncated(len(core)/8*8, ExpectPadding)
+		}
+		return false, "", err
+	}
+	return fixed != s, fixed, nil
+}
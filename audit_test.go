@@ -0,0 +1,43 @@
+package base8
+
+import "testing"
+
+func TestAuditPadding(t *testing.T) {
+	testCases := []struct {
+		input   string
+		fixable bool
+		fixed   string
+		offset  int // -1 means no error.
+	}{
+		{"", false, "", -1},
+		{"314=====", false, "314=====", -1},
+		{"31467557", false, "31467557", -1},
+		{"314==========", true, "314=====", -1},
+		{"314=====  ", true, "314=====", -1},
+		{"314674=====\r\n", true, "314674==", -1},
+		{"31467557==", true, "31467557", -1},
+		{"314", false, "", 0},
+		{"314==", false, "", 5},
+		{"314674=", false, "", 7},
+		{"314=====314=====", false, "", 8},
+		{"314==5==", false, "", 5},
+		{"314= =5", false, "", 6},
+		{"31x=====", false, "", 2},
+		{"3146=====", false, "", 4},
+	}
+	for _, tc := range testCases {
+		fixable, fixed, err := AuditPadding(tc.input)
+		testEqual(t, "AuditPadding(%q) fixable = %v, want %v", tc.input, fixable, tc.fixable)
+		testEqual(t, "AuditPadding(%q) fixed = %q, want %q", tc.input, fixed, tc.fixed)
+		if tc.offset == -1 {
+			testEqual(t, "AuditPadding(%q) error = %v, want %v", tc.input, err, error(nil))
+		} else {
+			testEqual(t, "AuditPadding(%q) error = %v, want %v", tc.input, corruptOffset(err), int64(tc.offset))
+		}
+		if err == nil {
+			if _, err := DecodeString(fixed); err != nil {
+				t.Errorf("AuditPadding(%q) = %q, which does not decode: %v", tc.input, fixed, err)
+			}
+		}
+	}
+}
@@ -2,73 +2,391 @@
 package base8
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"io"
+	"math"
+	"math/bits"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 /*
- * Encoder
+ * Encodings
  */
 
-const encodeTable = "01234567"
+// An Encoding is a radix 8 encoding/decoding scheme, defined by an
+// 8-character alphabet.
+type Encoding struct {
+	encode    [8]byte
+	pairs     [64]uint16 // pairs of symbols in output order, see setPairs
+	decodeMap [256]byte
+	padChar   rune
+	lsb       bool // symbols are packed least-significant bits first
+	swar      bool // the alphabet permits the SWAR fast paths
+	ignores   bool // some characters are marked ignoredIndex in decodeMap
+	strictEnd bool // data after terminal padding is an error
+	escape    bool // a short final quantum is escaped, see WithLengthEscape
+}
+
+const (
+	StdPadding rune = '=' // Standard padding character
+	NoPadding  rune = -1  // No padding
+)
+
+// PadChar is the standard padding character.
 const PadChar = '='
 
+const (
+	encodeStd       = "01234567"
+	encodeLetter    = "abcdefgh"
+	encodeSpeakSafe = "ailoruwx"
+)
+
+// decodeMap maps each byte to its symbol value, 0 through 7, or to one of
+// the classes below, so that the decoder classifies a byte with a single
+// lookup; any entry greater than 7 is not a symbol.
+const (
+	invalidIndex = '\xff' // the byte is not part of the encoding
+	paddingIndex = '\xfd' // the byte is the padding character
+)
+
+// NewEncoding returns a new Encoding defined by the given alphabet,
+// which must be an 8-byte string that does not contain the padding
+// character or CR / LF ('\r', '\n'). The alphabet is treated as a
+// sequence of byte values without any special treatment for multi-byte
+// UTF-8. The resulting Encoding uses the default padding character
+// ('='), which may be changed or disabled via WithPadding.
+func NewEncoding(encoder string) *Encoding {
+	if len(encoder) != 8 {
+		panic("encoding alphabet is not 8-bytes long")
+	}
+
+	e := new(Encoding)
+	e.padChar = StdPadding
+	copy(e.encode[:], encoder)
+	for i := 0; i < len(e.decodeMap); i++ {
+		e.decodeMap[i] = invalidIndex
+	}
+
+	for i := 0; i < len(encoder); i++ {
+		switch {
+		case encoder[i] == '\n', encoder[i] == '\r':
+			panic("encoding alphabet contains newline character")
+		case encoder[i] == byte(e.padChar):
+			panic("encoding alphabet contains padding character")
+		case e.decodeMap[encoder[i]] != invalidIndex:
+			panic("encoding alphabet includes duplicate symbols")
+		}
+		e.decodeMap[encoder[i]] = uint8(i)
+	}
+	e.decodeMap[e.padChar] = paddingIndex
+
+	// Whole quanta can be encoded, validated and converted eight bytes at a
+	// time if the alphabet is a run of consecutive bytes starting at a
+	// multiple of 8, as is the case for the standard alphabet.
+	e.swar = encoder[0]&7 == 0
+	for i := 1; i < len(encoder); i++ {
+		e.swar = e.swar && encoder[i] == encoder[0]+byte(i)
+	}
+	e.setPairs()
+	return e
+}
+
+// setPairs fills the pair table, which maps each 6-bit value to the two
+// symbols that encode it, in output order, as a little-endian uint16. For
+// MSBFirst the first symbol encodes the high 3 bits of the value; for
+// LSBFirst it encodes the low 3 bits.
+func (e *Encoding) setPairs() {
+	for i := range e.pairs {
+		hi, lo := uint16(e.encode[i>>3]), uint16(e.encode[i&7])
+		if e.lsb {
+			e.pairs[i] = lo | hi<<8
+		} else {
+			e.pairs[i] = hi | lo<<8
+		}
+	}
+}
+
+// WithPadding creates a new encoding identical to enc except
+// with a specified padding character, or NoPadding to disable padding.
+// The padding character must not be '\r' or '\n', must not
+// be contained in the encoding's alphabet, and must be a rune equal or
+// below '\xff'. This allows padded output in contexts where '=' is
+// reserved, such as query strings; the resulting encoding's decoder accepts
+// only the configured padding character.
+func (enc Encoding) WithPadding(padding rune) *Encoding {
+	if padding < NoPadding || padding == '\r' || padding == '\n' || padding > 0xff {
+		panic("invalid padding")
+	}
+
+	for i := 0; i < len(enc.encode); i++ {
+		if rune(enc.encode[i]) == padding {
+			panic("padding contained in alphabet")
+		}
+	}
+	if padding != NoPadding && enc.decodeMap[padding] <= 7 {
+		panic("padding contained in alphabet")
+	}
+	if padding != NoPadding && enc.decodeMap[padding] == ignoredIndex {
+		panic("padding is an ignored character")
+	}
+	if padding == NoPadding && enc.escape {
+		panic("length escape requires a padding character")
+	}
+
+	if enc.padChar != NoPadding {
+		enc.decodeMap[enc.padChar] = invalidIndex
+	}
+	if padding != NoPadding {
+		enc.decodeMap[padding] = paddingIndex
+	}
+	enc.padChar = padding
+	return &enc
+}
+
+// WithStrictEnd creates a new encoding identical to enc except that its
+// decoders reject any data that follows the padding of the final quantum,
+// rather than ignoring it. The error is a CorruptInputError that wraps
+// ErrInvalidPadding, expects ExpectEnd and locates the first trailing byte.
+// This matters when the encoded form itself must be unique, as for signed
+// tokens. It has no effect on encodings that use NoPadding, whose input ends
+// only at its end.
+func (enc Encoding) WithStrictEnd() *Encoding {
+	enc.strictEnd = true
+	return &enc
+}
+
+// A BitOrder specifies how an Encoding packs the 3-bit values of its symbols
+// into bytes.
+type BitOrder int
+
+const (
+	// MSBFirst packs each 3-byte group as a big-endian 24-bit value whose
+	// most significant bits are encoded by the first symbol. This is the
+	// standard bit order.
+	MSBFirst BitOrder = iota
+
+	// LSBFirst packs each 3-byte group as a little-endian 24-bit value whose
+	// least significant bits are encoded by the first symbol, for
+	// interoperability with hardware and firmware that emits octal digits in
+	// that order.
+	LSBFirst
+)
+
+// WithBitOrder creates a new encoding identical to enc except that it packs
+// symbols in the given bit order. Padding works the same way in either order:
+// a final quantum of 3 or 6 symbols holds 1 or 2 bytes.
+func (enc Encoding) WithBitOrder(order BitOrder) *Encoding {
+	switch order {
+	case MSBFirst, LSBFirst:
+	default:
+		panic("invalid bit order")
+	}
+	enc.lsb = order == LSBFirst
+	enc.setPairs()
+	return &enc
+}
+
+// BitReversed returns the encoding identical to enc except that it packs
+// symbols in the opposite bit order: LSBFirst for an MSBFirst encoding and
+// vice versa. The two are different codecs, so data encoded with one does
+// not in general decode to the same bytes with the other.
+func (enc *Encoding) BitReversed() *Encoding {
+	if enc.lsb {
+		return enc.WithBitOrder(MSBFirst)
+	}
+	return enc.WithBitOrder(LSBFirst)
+}
+
+// StdEncoding is the standard base8 encoding, which uses the digits 0-7
+// and pads its output with '='. The package-level functions are wrappers
+// around StdEncoding.
+//
+// Because its alphabet is in ascending byte order and its bit order is
+// MSBFirst, the unpadded standard encoding preserves order: for any byte
+// slices a and b, bytes.Compare(a, b) equals
+// strings.Compare(RawEncoding.EncodeToString(a), RawEncoding.EncodeToString(b)).
+// Padded output does not sort this way, since '=' sorts after the digits;
+// use Compare to compare padded encodings.
+var StdEncoding = NewEncoding(encodeStd)
+
+// RawEncoding is the standard unpadded base8 encoding. This is the same
+// as StdEncoding but omits padding characters: final quanta of 3 and 6
+// digits encode 1 and 2 bytes, respectively.
+var RawEncoding = StdEncoding.WithPadding(NoPadding)
+
+// LetterEncoding is an alternate padded base8 encoding that uses the letters
+// a-h in place of the digits 0-7, for contexts where all-digit strings are
+// problematic, such as phone autodialing or spreadsheets that coerce numeric
+// text. Its output contains no digits.
+var LetterEncoding = NewEncoding(encodeLetter)
+
+// NewSpeakSafeEncoding returns a padded base8 encoding for codes that are
+// read aloud, such as over the phone. Its alphabet is "ailoruwx": letters
+// whose English names do not rhyme with one another. Most letter names fall
+// into rhyming groups that are easily misheard over a poor line (a h j k;
+// b c d e g p t v z; f l m n s x; i y; q u), so the alphabet takes a, i and u
+// from three of them, l and x from the one group whose names still differ in
+// their consonants, and o, r and w, which rhyme with no other letter. The
+// encoder produces lowercase; the decoder accepts either case.
+func NewSpeakSafeEncoding() *Encoding {
+	return NewEncoding(encodeSpeakSafe).CaseInsensitive()
+}
+
+/*
+ * Encoder
+ */
+
 // Encode encodes src using the encoding enc, writing
 // EncodedLen(len(src)) bytes to dst.
 //
 // The encoding pads the output to a multiple of 8 bytes,
 // so Encode is not appropriate for use on individual blocks
 // of a large data stream. Use NewEncoder() instead.
-func Encode(dst, src []byte) {
+func (enc *Encoding) Encode(dst, src []byte) {
+	encode(enc, dst, src)
+}
+
+// spread returns the eight 3-bit groups of the 24-bit value v in the low bits
+// of the bytes of a uint64, least significant group in the least significant
+// byte.
+func spread(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<20) & 0x00000fff00000fff
+	x = (x | x<<10) & 0x003f003f003f003f
+	x = (x | x<<5) & 0x0707070707070707
+	return x
+}
+
+// encode implements Encode for both string and byte slice sources.
+func encode[T ~string | ~[]byte](enc *Encoding, dst []byte, src T) {
+	if enc.swar {
+		// For an alphabet that permits it, whole groups are encoded as a
+		// single uint64: each symbol is the first symbol ORed with the value
+		// of its group.
+		if useSIMD && len(src) >= 24 {
+			k := encodeSIMD(dst, data(src), len(src), enc.encode[0], enc.masks())
+			src = src[k:]
+			dst = dst[k/3*8:]
+		}
+		first := uint64(enc.encode[0]) * 0x0101010101010101
+		for len(src) >= 3 && len(dst) >= 8 {
+			var x uint64
+			if enc.lsb {
+				x = spread(uint32(src[0]) | uint32(src[1])<<8 | uint32(src[2])<<16)
+			} else {
+				x = bits.ReverseBytes64(spread(uint32(src[0])<<16 | uint32(src[1])<<8 | uint32(src[2])))
+			}
+			binary.LittleEndian.PutUint64(dst, x|first)
+			src = src[3:]
+			dst = dst[8:]
+		}
+	} else {
+		// Otherwise, whole groups are encoded two symbols at a time using
+		// the pair table.
+		for len(src) >= 3 && len(dst) >= 8 {
+			if enc.lsb {
+				v := uint32(src[0]) | uint32(src[1])<<8 | uint32(src[2])<<16
+				binary.LittleEndian.PutUint16(dst[0:], enc.pairs[v&0x3f])
+				binary.LittleEndian.PutUint16(dst[2:], enc.pairs[v>>6&0x3f])
+				binary.LittleEndian.PutUint16(dst[4:], enc.pairs[v>>12&0x3f])
+				binary.LittleEndian.PutUint16(dst[6:], enc.pairs[v>>18])
+			} else {
+				v := uint32(src[0])<<16 | uint32(src[1])<<8 | uint32(src[2])
+				binary.LittleEndian.PutUint16(dst[0:], enc.pairs[v>>18])
+				binary.LittleEndian.PutUint16(dst[2:], enc.pairs[v>>12&0x3f])
+				binary.LittleEndian.PutUint16(dst[4:], enc.pairs[v>>6&0x3f])
+				binary.LittleEndian.PutUint16(dst[6:], enc.pairs[v&0x3f])
+			}
+			src = src[3:]
+			dst = dst[8:]
+		}
+	}
+
 	for len(src) > 0 {
 		var b [8]byte
 
 		// Unpack 8x 3-bit source blocks into an 8 byte
 		// destination quantum
-		switch len(src) {
-		default:
-			b[7] = src[2] & 0x7        // bits [0:2]
-			b[6] = (src[2] >> 3) & 0x7 // bits [3:5]
-			b[5] = src[2] >> 6         // bits [6:7]
-			fallthrough
-		case 2:
-			b[5] |= (src[1] << 2) & 0x7 // bits [0:0]
-			b[4] = (src[1] >> 1) & 0x7  // bits [1:3]
-			b[3] = (src[1] >> 4) & 0x7  // bits [4:6]
-			b[2] = src[1] >> 7          // bits [7:7]
-			fallthrough
-		case 1:
-			b[2] |= (src[0] << 1) & 0x7 // bits [0:1]
-			b[1] = (src[0] >> 2) & 0x7  // bits [2:4]
-			b[0] = src[0] >> 5          // bits [5:7]
+		if enc.lsb {
+			var v uint32
+			switch len(src) {
+			default:
+				v |= uint32(src[2]) << 16
+				fallthrough
+			case 2:
+				v |= uint32(src[1]) << 8
+				fallthrough
+			case 1:
+				v |= uint32(src[0])
+			}
+			for i := range b {
+				b[i] = byte(v>>(3*i)) & 0x7
+			}
+		} else {
+			switch len(src) {
+			default:
+				b[7] = src[2] & 0x7        // bits [0:2]
+				b[6] = (src[2] >> 3) & 0x7 // bits [3:5]
+				b[5] = src[2] >> 6         // bits [6:7]
+				fallthrough
+			case 2:
+				b[5] |= (src[1] << 2) & 0x7 // bits [0:0]
+				b[4] = (src[1] >> 1) & 0x7  // bits [1:3]
+				b[3] = (src[1] >> 4) & 0x7  // bits [4:6]
+				b[2] = src[1] >> 7          // bits [7:7]
+				fallthrough
+			case 1:
+				b[2] |= (src[0] << 1) & 0x7 // bits [0:1]
+				b[1] = (src[0] >> 2) & 0x7  // bits [2:4]
+				b[0] = src[0] >> 5          // bits [5:7]
+			}
+		}
+
+		if len(src) < 3 && enc.escape {
+			// Escape the final quantum: the escape, the number of bytes
+			// it holds, then its first 3 or 6 symbols.
+			dst[0] = byte(enc.padChar)
+			dst[1] = enc.encode[len(src)]
+			for i := 0; i < len(src)*3; i++ {
+				dst[2+i] = enc.encode[b[i]&7]
+			}
+			break
 		}
 
 		// Encode 3-bit blocks using the base8 alphabet
 		size := len(dst)
 		if size >= 8 {
 			// Common case, unrolled for extra performance
-			dst[0] = encodeTable[b[0]&7]
-			dst[1] = encodeTable[b[1]&7]
-			dst[2] = encodeTable[b[2]&7]
-			dst[3] = encodeTable[b[3]&7]
-			dst[4] = encodeTable[b[4]&7]
-			dst[5] = encodeTable[b[5]&7]
-			dst[6] = encodeTable[b[6]&7]
-			dst[7] = encodeTable[b[7]&7]
+			dst[0] = enc.encode[b[0]&7]
+			dst[1] = enc.encode[b[1]&7]
+			dst[2] = enc.encode[b[2]&7]
+			dst[3] = enc.encode[b[3]&7]
+			dst[4] = enc.encode[b[4]&7]
+			dst[5] = enc.encode[b[5]&7]
+			dst[6] = enc.encode[b[6]&7]
+			dst[7] = enc.encode[b[7]&7]
 		} else {
 			for i := 0; i < size; i++ {
-				dst[i] = encodeTable[b[i]&7]
+				dst[i] = enc.encode[b[i]&7]
 			}
 		}
 
 		// Pad the final quantum
 		if len(src) < 3 {
-			dst[7] = PadChar
-			dst[6] = PadChar
+			if enc.padChar == NoPadding {
+				break
+			}
+			dst[7] = byte(enc.padChar)
+			dst[6] = byte(enc.padChar)
 			if len(src) < 2 {
-				dst[5] = PadChar
-				dst[4] = PadChar
-				dst[3] = PadChar
+				dst[5] = byte(enc.padChar)
+				dst[4] = byte(enc.padChar)
+				dst[3] = byte(enc.padChar)
 			}
 
 			break
@@ -79,25 +397,131 @@ func Encode(dst, src []byte) {
 	}
 }
 
+// Encode encodes src using the standard encoding, writing
+// EncodedLen(len(src)) bytes to dst.
+func Encode(dst, src []byte) {
+	StdEncoding.Encode(dst, src)
+}
+
 // EncodeToString returns the base8 encoding of src.
+func (enc *Encoding) EncodeToString(src []byte) string {
+	return encodeToString(enc, src)
+}
+
+// encodeToString implements EncodeToString for both string and byte slice
+// sources.
+func encodeToString[T ~string | ~[]byte](enc *Encoding, src T) string {
+	// Encode via a stack buffer so that the only allocation is the result.
+	var sb strings.Builder
+	sb.Grow(enc.EncodedLen(len(src)))
+	var buf [1024]byte
+	for len(src) > 0 {
+		nn := len(buf) / 8 * 3
+		if nn > len(src) {
+			nn = len(src)
+		}
+		encode(enc, buf[0:], src[0:nn])
+		sb.Write(buf[0:enc.EncodedLen(nn)])
+		src = src[nn:]
+	}
+	return sb.String()
+}
+
+// EncodeToString returns the standard base8 encoding of src.
 func EncodeToString(src []byte) string {
-	buf := make([]byte, EncodedLen(len(src)))
-	Encode(buf, src)
-	return string(buf)
+	return StdEncoding.EncodeToString(src)
+}
+
+// EncodeToStringAny is like EncodeToString, but accepts either a string or a
+// byte slice, so that callers holding a string need not copy it into a byte
+// slice first.
+func EncodeToStringAny[T ~string | ~[]byte](src T) string {
+	return encodeToString(StdEncoding, src)
+}
+
+// AppendEncode appends the base8 encoded src to dst
+// and returns the extended buffer.
+func (enc *Encoding) AppendEncode(dst, src []byte) []byte {
+	n := enc.EncodedLen(len(src))
+	dst = slices.Grow(dst, n)
+	enc.Encode(dst[len(dst):][:n], src)
+	return dst[:len(dst)+n]
+}
+
+// AppendEncode appends the standard base8 encoded src to dst
+// and returns the extended buffer.
+func AppendEncode(dst, src []byte) []byte {
+	return StdEncoding.AppendEncode(dst, src)
 }
 
 type encoder struct {
 	err  error
+	enc  *Encoding
 	w    io.Writer
-	buf  [3]byte    // buffered data waiting to be encoded
-	nbuf int        // number of bytes in buf
-	out  [1024]byte // output buffer
+	buf  [3]byte        // buffered data waiting to be encoded
+	nbuf int            // number of bytes in buf
+	out  []byte         // output buffer
+	bufs *streamBuffers // pooled buffers backing out, if any
+}
+
+// defaultBufSize is the size in bytes of the buffers used by the encoders
+// and decoders returned by NewEncoder and NewDecoder.
+const defaultBufSize = 1024
+
+// streamBuffers holds the buffers of an encoder or decoder of the default
+// size. They come from bufPool, so that servers that create a stream per
+// request do not allocate them each time, and are returned to it once the
+// stream is finished.
+type streamBuffers struct {
+	encoded [defaultBufSize]byte
+	decoded [defaultBufSize / 8 * 3]byte
+}
+
+var bufPool = sync.Pool{New: func() any { return new(streamBuffers) }}
+
+// streamBufSize returns size rounded down to a whole number of quanta, and at
+// least one quantum.
+func streamBufSize(size int) int {
+	if size < 8 {
+		return 8
+	}
+	return size / 8 * 8
 }
 
 func (e *encoder) Write(p []byte) (n int, err error) {
+	return write(e, p)
+}
+
+// WriteString is like Write, but accepts a string, avoiding the copy that
+// converting it to a byte slice would require.
+func (e *encoder) WriteString(s string) (n int, err error) {
+	return write(e, s)
+}
+
+// acquire makes sure that the encoder has an output buffer, taking it from
+// bufPool if the encoder has none.
+func (e *encoder) acquire() {
+	if e.out == nil {
+		e.bufs = bufPool.Get().(*streamBuffers)
+		e.out = e.bufs.encoded[0:]
+	}
+}
+
+// release returns the encoder's output buffer to bufPool if it came from
+// there.
+func (e *encoder) release() {
+	if e.bufs != nil {
+		bufPool.Put(e.bufs)
+		e.bufs, e.out = nil, nil
+	}
+}
+
+// write implements Write and WriteString.
+func write[T ~string | ~[]byte](e *encoder, p T) (n int, err error) {
 	if e.err != nil {
 		return 0, e.err
 	}
+	e.acquire()
 
 	// Leading fringe.
 	if e.nbuf > 0 {
@@ -111,7 +535,7 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 		if e.nbuf < 3 {
 			return
 		}
-		Encode(e.out[0:], e.buf[0:])
+		e.enc.Encode(e.out[0:], e.buf[0:])
 		if _, e.err = e.w.Write(e.out[0:8]); e.err != nil {
 			return n, e.err
 		}
@@ -125,7 +549,7 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 			nn = len(p)
 			nn -= nn % 3
 		}
-		Encode(e.out[0:], p[0:nn])
+		encode(e.enc, e.out[0:], p[0:nn])
 		if _, e.err = e.w.Write(e.out[0 : nn/3*8]); e.err != nil {
 			return n, e.err
 		}
@@ -142,13 +566,95 @@ func (e *encoder) Write(p []byte) (n int, err error) {
 	return
 }
 
+// ReadFrom encodes the data read from r until EOF, reading directly into the
+// encoder's output buffer rather than through an intermediate buffer. It
+// implements io.ReaderFrom, so io.Copy uses it automatically. As with Write,
+// any trailing partial block is buffered until the encoder is closed.
+func (e *encoder) ReadFrom(r io.Reader) (n int64, err error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	e.acquire()
+
+	// Raw data is read into the last 3/8 of the buffer and encoded in place
+	// into the whole buffer. Each group is loaded before its output is
+	// stored, and the output never catches up with the groups still to be
+	// loaded.
+	nn := len(e.out) / 8 * 3
+	raw := e.out[len(e.out)-nn:]
+	for {
+		m := copy(raw, e.buf[0:e.nbuf])
+		var nr int
+		nr, err = r.Read(raw[m:])
+		n += int64(nr)
+		m += nr
+
+		whole := m - m%3
+		if whole > 0 {
+			encode(e.enc, e.out[0:], raw[0:whole])
+			if _, e.err = e.w.Write(e.out[0 : whole/3*8]); e.err != nil {
+				e.nbuf = 0
+				return n, e.err
+			}
+		}
+		e.nbuf = copy(e.buf[0:], raw[whole:m])
+
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Flush writes any buffered partial block to the underlying writer, padded
+// as though the stream ended there, so that everything written so far can be
+// decoded. Unlike Close, Flush leaves the encoder usable: subsequent writes
+// start a new block. If a padded block was flushed and more data follows,
+// the output is a concatenation of separately padded messages, each of which
+// must be decoded on its own, for example with DecodeConcat or
+// NewMessageDecoder. Encodings that use NoPadding cannot mark the end of a
+// partial block, so for them Flush writes nothing and the partial block stays
+// buffered until more data or Close.
+func (e *encoder) Flush() error {
+	if e.enc.padChar == NoPadding {
+		return e.err
+	}
+	return e.flush()
+}
+
+// Reset discards any buffered data and error state and makes the encoder
+// write to w, so that an encoder can be reused, for example from a
+// sync.Pool, without allocating a new one.
+func (e *encoder) Reset(w io.Writer) {
+	e.err = nil
+	e.w = w
+	e.nbuf = 0
+}
+
+// Err returns the error, if any, that made the encoder fail: the first error
+// returned by the underlying writer. Once it is non-nil, every call to
+// Write and Close returns it.
+func (e *encoder) Err() error {
+	return e.err
+}
+
 // Close flushes any pending output from the encoder.
 // It is an error to call Write after calling Close.
 func (e *encoder) Close() error {
+	err := e.flush()
+	e.release()
+	return err
+}
+
+// flush implements Flush and Close.
+func (e *encoder) flush() error {
 	// If there's anything left in the buffer, flush it out
 	if e.err == nil && e.nbuf > 0 {
-		Encode(e.out[0:], e.buf[0:e.nbuf])
-		encodedLen := EncodedLen(e.nbuf)
+		e.acquire()
+		e.enc.Encode(e.out[0:], e.buf[0:e.nbuf])
+		encodedLen := e.enc.EncodedLen(e.nbuf)
 		e.nbuf = 0
 		_, e.err = e.w.Write(e.out[0:encodedLen])
 	}
@@ -159,89 +665,466 @@ func (e *encoder) Close() error {
 // the returned writer will be encoded using enc and then written to w.
 // Base8 operates in 3-byte blocks; when finished writing, the caller
 // must Close the returned encoder to flush any partially written
-// blocks.
+// blocks. If enc uses NoPadding, as RawEncoding does, Close writes the
+// final partial block as 3 or 6 digits without padding. The returned
+// encoder implements io.StringWriter, io.ReaderFrom,
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler. It also has a
+// Flush() error method that does the same without ending the stream, a
+// Reset(io.Writer) method that prepares it for reuse with a new writer, and an
+// Err() error method that reports why it failed.
+//
+// The encoder's output buffer is shared through an internal pool: it is taken
+// on the first write and returned by Close, so that creating an encoder per
+// request does not allocate a buffer each time.
+func (enc *Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return enc.NewEncoderSize(w, defaultBufSize)
+}
+
+// NewEncoder returns a new base8 stream encoder that uses the standard
+// encoding.
 func NewEncoder(w io.Writer) io.WriteCloser {
-	return &encoder{w: w}
+	return StdEncoding.NewEncoder(w)
+}
+
+// NewEncoderSize is like NewEncoder, but the returned encoder buffers up to
+// size bytes of encoded output per write to w instead of the default 1024.
+// Larger buffers mean fewer writes for bulk data; smaller ones save memory.
+// size is rounded down to a multiple of 8, and values below 8 are treated
+// as 8.
+func (enc *Encoding) NewEncoderSize(w io.Writer, size int) io.WriteCloser {
+	e := &encoder{enc: enc, w: w}
+	if size = streamBufSize(size); size != defaultBufSize {
+		// Only buffers of the default size are pooled; see acquire.
+		e.out = make([]byte, size)
+	}
+	return e
+}
+
+// NewEncoderSize is like NewEncoder, but the returned encoder uses an output
+// buffer of the given size.
+func NewEncoderSize(w io.Writer, size int) io.WriteCloser {
+	return StdEncoding.NewEncoderSize(w, size)
 }
 
 // EncodedLen returns the length in bytes of the base8 encoding
 // of an input buffer of length n.
+func (enc *Encoding) EncodedLen(n int) int {
+	switch {
+	case enc.padChar == NoPadding:
+		return EncodedLenNoPad(n)
+	case enc.escape:
+		return EncodedLenNoPad(n) + (n%3+2)/3*2
+	}
+	return n/3*8 + (n%3+2)/3*8
+}
+
+// EncodedLen returns the length in bytes of the standard base8 encoding
+// of an input buffer of length n.
 func EncodedLen(n int) int {
-	return (n + 2) / 3 * 8
+	return StdEncoding.EncodedLen(n)
+}
+
+// EncodedLen64 is like EncodedLen, but operates on int64 lengths so that it
+// can size the output for inputs larger than an int can describe, such as
+// multi-gigabyte files on 32-bit platforms. The result is undefined if it does
+// not fit in an int64; use CheckedEncodedLen to detect overflow.
+func (enc *Encoding) EncodedLen64(n int64) int64 {
+	switch {
+	case enc.padChar == NoPadding:
+		return n/3*8 + n%3*3
+	case enc.escape:
+		return n/3*8 + n%3*3 + (n%3+2)/3*2
+	}
+	return n/3*8 + (n%3+2)/3*8
+}
+
+// EncodedLen64 returns the length in bytes of the standard base8 encoding
+// of an input of length n.
+func EncodedLen64(n int64) int64 {
+	return StdEncoding.EncodedLen64(n)
+}
+
+// ErrLengthOverflow is returned by CheckedEncodedLen when the encoded length
+// cannot be represented as an int.
+var ErrLengthOverflow = errors.New("base8: encoded length overflows int")
+
+// CheckedEncodedLen returns the length in bytes of the base8 encoding of an
+// input of length n, or ErrLengthOverflow if n is negative or the encoded
+// length does not fit in an int. Unlike EncodedLen, its result is always
+// safe to use as the size of an output buffer.
+func (enc *Encoding) CheckedEncodedLen(n int64) (int, error) {
+	if n < 0 {
+		return 0, ErrLengthOverflow
+	}
+	tail := enc.EncodedLen64(n % 3)
+	if n/3 > (math.MaxInt-tail)/8 {
+		return 0, ErrLengthOverflow
+	}
+	return int(n/3*8 + tail), nil
+}
+
+// CheckedEncodedLen returns the length in bytes of the standard base8
+// encoding of an input of length n, or ErrLengthOverflow if that length does
+// not fit in an int.
+func CheckedEncodedLen(n int64) (int, error) {
+	return StdEncoding.CheckedEncodedLen(n)
+}
+
+// EncodedLenNoPad returns the length in bytes of the unpadded base8
+// encoding of an input buffer of length n. A final quantum holding one
+// byte needs 3 digits, and one holding two bytes needs 6. This is the
+// value of EncodedLen for encodings that use NoPadding.
+func EncodedLenNoPad(n int) int {
+	return n/3*8 + n%3*3
+}
+
+// SizeReport returns the length of src along with the lengths of its padded
+// and unpadded base8 encodings.
+func SizeReport(src []byte) (raw, encoded, encodedNoPad int) {
+	return len(src), EncodedLen(len(src)), EncodedLenNoPad(len(src))
 }
 
 /*
  * Decoder
  */
 
-type CorruptInputError int64
+// An Expectation describes what a decoder expected to find when it
+// encountered corrupt input.
+type Expectation int
+
+const (
+	ExpectSymbol  Expectation = iota // a symbol (digit) of the alphabet
+	ExpectPadding                    // a padding character
+	ExpectEnd                        // the end of the input
+)
+
+func (x Expectation) String() string {
+	switch x {
+	case ExpectSymbol:
+		return "symbol"
+	case ExpectPadding:
+		return "padding"
+	case ExpectEnd:
+		return "end"
+	default:
+		return "Expectation(" + strconv.Itoa(int(x)) + ")"
+	}
+}
+
+var (
+	// ErrInvalidCharacter is wrapped by a CorruptInputError caused by a
+	// byte that is neither a symbol of the alphabet nor padding.
+	ErrInvalidCharacter = errors.New("base8: invalid character")
+
+	// ErrInvalidPadding is wrapped by a CorruptInputError caused by
+	// padding that is missing, misplaced or followed by other data.
+	ErrInvalidPadding = errors.New("base8: invalid padding")
+
+	// ErrInvalidLength is wrapped by a CorruptInputError caused by input
+	// that ends before its final quantum is complete.
+	ErrInvalidLength = errors.New("base8: invalid length")
+
+	// ErrUppercase is wrapped by a CorruptInputError caused by the
+	// uppercase form of a lowercase letter of the alphabet, which the
+	// decoder rejects unless the encoding is CaseInsensitive.
+	ErrUppercase = errors.New("base8: unexpected uppercase character")
+)
+
+// A CorruptInputError is returned when the input is not valid base8. It
+// describes where decoding failed, the offending byte and what was expected
+// instead, and wraps one of ErrInvalidCharacter, ErrInvalidPadding,
+// ErrInvalidLength or ErrUppercase to classify the failure.
+type CorruptInputError struct {
+	offset   int64
+	b        byte
+	expected Expectation
+	err      error
+}
+
+// invalidSymbol returns the error for the byte b at offset off, which was
+// found where a symbol was expected.
+func (enc *Encoding) invalidSymbol(off int, b byte) CorruptInputError {
+	err := ErrInvalidCharacter
+	switch {
+	case enc.decodeMap[b] == paddingIndex:
+		err = ErrInvalidPadding
+	case 'A' <= b && b <= 'Z' && enc.decodeMap[b+'a'-'A'] <= 7:
+		err = ErrUppercase
+	}
+	return CorruptInputError{offset: int64(off), b: b, expected: ExpectSymbol, err: err}
+}
+
+// invalidPadding returns the error for the byte b at offset off, which was
+// found where padding was expected.
+func invalidPadding(off int, b byte) CorruptInputError {
+	return CorruptInputError{offset: int64(off), b: b, expected: ExpectPadding, err: ErrInvalidPadding}
+}
+
+// truncated returns the error for input that ended in the quantum at offset
+// off while more input of the expected kind was required.
+func truncated(off int, expected Expectation) CorruptInputError {
+	return CorruptInputError{offset: int64(off), expected: expected, err: ErrInvalidLength}
+}
+
+// trailing returns the error for the byte b at offset off, which follows
+// terminal padding.
+func trailing(off int, b byte) CorruptInputError {
+	return CorruptInputError{offset: int64(off), b: b, expected: ExpectEnd, err: ErrInvalidPadding}
+}
+
+// shift returns a copy of e with its offset moved by delta bytes. It is used
+// to turn an offset within a piece of the input into one within the whole.
+func (e CorruptInputError) shift(delta int64) CorruptInputError {
+	e.offset += delta
+	return e
+}
+
+// Offset returns the offset in bytes into the input at which the corruption
+// was detected. If the input is malformed padding, this is the offset at
+// which the padding begins to go wrong; if the input ends in the middle of a
+// quantum, it is the offset of that quantum.
+func (e CorruptInputError) Offset() int64 {
+	return e.offset
+}
+
+// Byte returns the offending byte, or 0 if the input ended before the
+// final quantum was complete.
+func (e CorruptInputError) Byte() byte {
+	return e.b
+}
+
+// Expected returns what the decoder expected to find instead of the
+// offending byte.
+func (e CorruptInputError) Expected() Expectation {
+	return e.expected
+}
 
 func (e CorruptInputError) Error() string {
-	return "illegal base8 data at input byte " + strconv.FormatInt(int64(e), 10)
+	return "illegal base8 data at input byte " + strconv.FormatInt(e.offset, 10)
+}
+
+func (e CorruptInputError) Unwrap() error {
+	return e.err
+}
+
+// Recoverable reports whether err is, or wraps, a CorruptInputError caused by
+// a bad byte: an invalid character, an unexpected uppercase character or
+// misplaced padding. Skipping the quantum
+// that holds such a byte, as DecodeRecover does, may recover the data that
+// follows it. Recoverable reports false for an ErrInvalidLength error, input
+// that ends before its final quantum is complete, which no amount of skipping
+// repairs, and for errors that do not describe corrupt input.
+func Recoverable(err error) bool {
+	var e CorruptInputError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.err != ErrInvalidLength
+}
+
+// load64 returns the first 8 bytes of src as a little-endian uint64.
+func load64[T ~string | ~[]byte](src T) uint64 {
+	_ = src[7] // bounds check hint to compiler
+	return uint64(src[0]) | uint64(src[1])<<8 | uint64(src[2])<<16 | uint64(src[3])<<24 |
+		uint64(src[4])<<32 | uint64(src[5])<<40 | uint64(src[6])<<48 | uint64(src[7])<<56
+}
+
+// gather returns the 3-bit values in the low bits of the bytes of x packed
+// into a 24-bit value, the value of the least significant byte in the least
+// significant bits. It is the inverse of spread.
+func gather(x uint64) uint32 {
+	x = (x | x>>5) & 0x003f003f003f003f
+	x = (x | x>>10) & 0x00000fff00000fff
+	x = (x | x>>20) & 0x0000000000ffffff
+	return uint32(x)
 }
 
 // decode is like Decode but returns an additional 'end' value, which
 // indicates if end-of-message padding was encountered and thus any
 // additional data is an error.
-func decode(dst, src []byte) (n int, end bool, err error) {
-	dsti := 0
+func (enc *Encoding) decode(dst, src []byte) (n int, end bool, err error) {
+	return decode(enc, dst, src)
+}
+
+// decode implements the decode method for both byte slices and strings, so
+// that strings can be decoded without first being copied.
+func decode[T ~string | ~[]byte](enc *Encoding, dst []byte, src T) (n int, end bool, err error) {
+	if enc.ignores {
+		n, end, err = decodeStripped(enc, dst, stripIgnored(enc, src))
+		return n, end, unstrip(enc, src, err)
+	}
+	return decodeStripped(enc, dst, src)
+}
+
+// decodeStripped implements decode for input that contains no ignored
+// characters.
+func decodeStripped[T ~string | ~[]byte](enc *Encoding, dst []byte, src T) (n int, end bool, err error) {
+	if enc.escape {
+		return decodeEscaped(enc, dst, src)
+	}
+	return decodeQuanta(enc, dst, src)
+}
+
+// decodeQuanta implements decode for input that contains no ignored
+// characters.
+func decodeQuanta[T ~string | ~[]byte](enc *Encoding, dst []byte, src T) (n int, end bool, err error) {
 	olen := len(src)
 
+	if enc.swar {
+		// For an alphabet that permits it, a complete quantum can be
+		// validated and converted as a single uint64: a byte is a symbol iff
+		// its top five bits match those of the first symbol, in which case
+		// XORing it with the first symbol yields its value. The first quantum
+		// that is not all symbols is left to the slow path below.
+		if useSIMD && len(src) >= 32 {
+			k := decodeSIMD(dst, data(src), len(src), enc.encode[0], enc.masks())
+			n += k / 8 * 3
+			dst = dst[k/8*3:]
+			src = src[k:]
+		}
+		first := uint64(enc.encode[0]) * 0x0101010101010101
+		for len(src) >= 8 {
+			v := load64(src) ^ first
+			if v&0xf8f8f8f8f8f8f8f8 != 0 {
+				break
+			}
+			_ = dst[2] // bounds check hint to compiler
+			if enc.lsb {
+				x := gather(v)
+				dst[0] = byte(x)
+				dst[1] = byte(x >> 8)
+				dst[2] = byte(x >> 16)
+			} else {
+				x := gather(bits.ReverseBytes64(v))
+				dst[0] = byte(x >> 16)
+				dst[1] = byte(x >> 8)
+				dst[2] = byte(x)
+			}
+			n += 3
+			dst = dst[3:]
+			src = src[8:]
+		}
+	} else {
+		// Otherwise, a complete quantum is looked up in decodeMap a byte at a
+		// time, but validated once: every entry that is not a symbol sets a
+		// bit above the low three.
+		for len(src) >= 8 {
+			q := src[:8]
+			var dbuf [8]byte
+			for j := range dbuf {
+				dbuf[j] = enc.decodeMap[q[j]]
+			}
+			if dbuf[0]|dbuf[1]|dbuf[2]|dbuf[3]|dbuf[4]|dbuf[5]|dbuf[6]|dbuf[7] > 7 {
+				break
+			}
+			_ = dst[2] // bounds check hint to compiler
+			if enc.lsb {
+				dst[0] = dbuf[0] | dbuf[1]<<3 | dbuf[2]<<6
+				dst[1] = dbuf[2]>>2 | dbuf[3]<<1 | dbuf[4]<<4 | dbuf[5]<<7
+				dst[2] = dbuf[5]>>1 | dbuf[6]<<2 | dbuf[7]<<5
+			} else {
+				dst[0] = dbuf[0]<<5 | dbuf[1]<<2 | dbuf[2]>>1
+				dst[1] = dbuf[2]<<7 | dbuf[3]<<4 | dbuf[4]<<1 | dbuf[5]>>2
+				dst[2] = dbuf[5]<<6 | dbuf[6]<<3 | dbuf[7]
+			}
+			n += 3
+			dst = dst[3:]
+			src = src[8:]
+		}
+	}
+
 	for len(src) > 0 && !end {
 		// Decode quantum using the base8 alphabet
 		var dbuf [8]byte
 		dlen := 8
 
+		// Slow path: handle padding and locate any illegal byte.
 		for j := 0; j < 8; {
 			if len(src) == 0 {
-				// We have reached the end and are missing padding
-				return n, false, CorruptInputError(olen - len(src) - j)
+				if enc.padChar != NoPadding {
+					// We have reached the end and are missing padding
+					return n, false, truncated(olen-j, ExpectPadding)
+				}
+				if j != 3 && j != 6 {
+					// We have reached the end in the middle of a symbol
+					return n, false, truncated(olen-j, ExpectSymbol)
+				}
+				// We have reached the end and are not expecting any padding
+				dlen, end = j, true
+				break
 			}
 			in := src[0]
 			src = src[1:]
-			if in == byte(PadChar) && j >= 2 && len(src) < 8 {
+			dbuf[j] = enc.decodeMap[in]
+			if dbuf[j] == paddingIndex && j >= 2 && len(src) < 8 {
 				// We've reached the end and there's padding
 				if len(src)+j < 8-1 {
 					// not enough padding
-					return n, false, CorruptInputError(olen)
+					return n, false, truncated(olen, ExpectPadding)
 				}
 				for k := 0; k < 8-1-j; k++ {
-					if len(src) > k && src[k] != byte(PadChar) {
+					if len(src) > k && enc.decodeMap[src[k]] != paddingIndex {
 						// incorrect padding
-						return n, false, CorruptInputError(olen - len(src) + k - 1)
+						return n, false, invalidPadding(olen-len(src)+k-1, src[k])
 					}
 				}
 				dlen, end = j, true
 				// 5 and 2 are the only valid padding lengths, so 3 and 6 are the only
 				// valid dlen values.
 				if dlen != 3 && dlen != 6 {
-					return n, false, CorruptInputError(olen - len(src) - 1)
+					return n, false, enc.invalidSymbol(olen-len(src)-1, in)
+				}
+				if enc.strictEnd && len(src) > 8-1-j {
+					return n, false, trailing(olen-len(src)+8-1-j, src[8-1-j])
 				}
 				break
 			}
-			dbuf[j] = in - '0'
 			if dbuf[j] > 7 {
-				return n, false, CorruptInputError(olen - len(src) - 1)
+				return n, false, enc.invalidSymbol(olen-len(src)-1, in)
 			}
 			j++
 		}
 
-		// Pack 8x 3-bit source blocks into 3 byte destination
-		// quantum
-		switch dlen {
-		case 8:
-			dst[dsti+2] = dbuf[5]<<6 | dbuf[6]<<3 | dbuf[7]
-			n++
-			fallthrough
-		case 6:
-			dst[dsti+1] = dbuf[2]<<7 | dbuf[3]<<4 | dbuf[4]<<1 | dbuf[5]>>2
-			n++
-			fallthrough
-		case 3:
-			dst[dsti] = dbuf[0]<<5 | dbuf[1]<<2 | dbuf[2]>>1
-			n++
+		if enc.lsb {
+			// Pack 8x 3-bit source blocks into 3 byte destination
+			// quantum, least significant bits first
+			v := uint32(dbuf[0]) | uint32(dbuf[1])<<3 | uint32(dbuf[2])<<6 | uint32(dbuf[3])<<9 |
+				uint32(dbuf[4])<<12 | uint32(dbuf[5])<<15 | uint32(dbuf[6])<<18 | uint32(dbuf[7])<<21
+			switch dlen {
+			case 8:
+				dst[2] = byte(v >> 16)
+				n++
+				fallthrough
+			case 6:
+				dst[1] = byte(v >> 8)
+				n++
+				fallthrough
+			case 3:
+				dst[0] = byte(v)
+				n++
+			}
+		} else {
+			// Pack 8x 3-bit source blocks into 3 byte destination
+			// quantum
+			switch dlen {
+			case 8:
+				dst[2] = dbuf[5]<<6 | dbuf[6]<<3 | dbuf[7]
+				n++
+				fallthrough
+			case 6:
+				dst[1] = dbuf[2]<<7 | dbuf[3]<<4 | dbuf[4]<<1 | dbuf[5]>>2
+				n++
+				fallthrough
+			case 3:
+				dst[0] = dbuf[0]<<5 | dbuf[1]<<2 | dbuf[2]>>1
+				n++
+			}
+		}
+		if end {
+			// A partial final quantum may have a shorter dst.
+			break
 		}
-		dsti += 3
+		dst = dst[3:]
 	}
 	return n, end, nil
 }
@@ -250,46 +1133,417 @@ func decode(dst, src []byte) (n int, end bool, err error) {
 // DecodedLen(len(src)) bytes to dst and returns the number of bytes
 // written. If src contains invalid base8 data, it will return the
 // number of bytes successfully written and CorruptInputError.
+func (enc *Encoding) Decode(dst, src []byte) (n int, err error) {
+	n, _, err = enc.decode(dst, src)
+	return
+}
+
+// Decode decodes src using the standard encoding. It writes at most
+// DecodedLen(len(src)) bytes to dst and returns the number of bytes
+// written.
 func Decode(dst, src []byte) (n int, err error) {
-	n, _, err = decode(dst, src)
+	return StdEncoding.Decode(dst, src)
+}
+
+// DecodeAny is like Decode, but accepts either a string or a byte slice, so
+// that callers holding a string need not copy it into a byte slice first.
+func DecodeAny[T ~string | ~[]byte](dst []byte, src T) (n int, err error) {
+	n, _, err = decode(StdEncoding, dst, src)
 	return
 }
 
+// validate implements Validate and IsValid without allocating.
+func validate[T ~string | ~[]byte](enc *Encoding, src T) (int, error) {
+	var err error
+	switch {
+	case enc.ignores && enc.escape:
+		err = unstrip(enc, src, validateEscaped(enc, stripIgnored(enc, src)))
+	case enc.ignores:
+		err = unstrip(enc, src, validateQuanta(enc, stripIgnored(enc, src)))
+	case enc.escape:
+		err = validateEscaped(enc, src)
+	default:
+		err = validateQuanta(enc, src)
+	}
+	if err != nil {
+		return int(err.(CorruptInputError).offset), err
+	}
+	return len(src), nil
+}
+
+// validateQuanta implements validate for input that contains no ignored
+// characters.
+func validateQuanta[T ~string | ~[]byte](enc *Encoding, src T) error {
+	// Padding is only legal in the last quantum, so any quantum that is
+	// followed by at least one more complete quantum must consist entirely
+	// of symbols.
+	i := 0
+	for ; len(src)-i >= 16; i += 8 {
+		q := src[i : i+8]
+		for j := 0; j < len(q); j++ {
+			if enc.decodeMap[q[j]] > 7 {
+				return enc.invalidSymbol(i+j, q[j])
+			}
+		}
+	}
+
+	// Validate the remaining (at most two) quanta by decoding them.
+	var tail [16]byte
+	var dbuf [6]byte
+	if _, _, err := decodeQuanta(enc, dbuf[0:], tail[0:copy(tail[0:], src[i:])]); err != nil {
+		return err.(CorruptInputError).shift(int64(i))
+	}
+	return nil
+}
+
+// Validate checks that src is valid base8 without decoding it. If src is
+// valid, Validate returns len(src) and a nil error. Otherwise, it returns the
+// offset of the first invalid byte and a CorruptInputError, exactly as
+// Decode would report them. Validate does not allocate unless enc ignores
+// characters.
+func (enc *Encoding) Validate(src []byte) (int, error) {
+	return validate(enc, src)
+}
+
+// Validate checks that src is valid standard base8 without decoding it.
+func Validate(src []byte) (int, error) {
+	return StdEncoding.Validate(src)
+}
+
+// IsValid reports whether s is valid base8. IsValid does not allocate unless
+// enc ignores characters.
+func (enc *Encoding) IsValid(s string) bool {
+	_, err := validate(enc, s)
+	return err == nil
+}
+
+// IsValid reports whether s is valid standard base8.
+func IsValid(s string) bool {
+	return StdEncoding.IsValid(s)
+}
+
+// DecodeConsumed is like Decode, but also returns the number of bytes of src
+// that were consumed. Decoding stops after the first quantum that ends in
+// padding, or after the escaped tail of an encoding with a length escape, so
+// src may be followed by data that is not base8 and nSrc reports where that
+// data begins. If src contains invalid base8 data, nSrc is the
+// number of bytes in the quanta that were successfully decoded and err is a
+// CorruptInputError.
+func (enc *Encoding) DecodeConsumed(dst, src []byte) (nDst, nSrc int, err error) {
+	for {
+		e := enc.quantumEnd(src, nSrc)
+		if e < 0 {
+			break
+		}
+		n, end, err := enc.decode(dst[nDst:], src[nSrc:e])
+		if err != nil {
+			return nDst, nSrc, err.(CorruptInputError).shift(int64(nSrc))
+		}
+		nDst, nSrc = nDst+n, e
+		if end {
+			return nDst, nSrc, nil
+		}
+	}
+	if nSrc < len(src) {
+		n, _, err := enc.decode(dst[nDst:], src[nSrc:])
+		if err != nil {
+			return nDst, nSrc, err.(CorruptInputError).shift(int64(nSrc))
+		}
+		nDst, nSrc = nDst+n, len(src)
+	}
+	return nDst, nSrc, nil
+}
+
+// quantumEnd returns the offset just past the quantum that starts at offset i
+// of src, or -1 if src does not hold a complete quantum there. A quantum is 8
+// bytes that are not ignored by enc, or for an encoding with a length escape,
+// an escaped tail of the length given by its count.
+func (enc *Encoding) quantumEnd(src []byte, i int) int {
+	if !enc.ignores && !enc.escape {
+		if len(src)-i < 8 {
+			return -1
+		}
+		return i + 8
+	}
+	size, first := 8, byte(invalidIndex)
+	for n := 0; i < len(src); i++ {
+		v := enc.decodeMap[src[i]]
+		if v == ignoredIndex {
+			continue
+		}
+		switch {
+		case n == 0:
+			first = v
+		case n == 1 && enc.escape && first == paddingIndex && (v == 1 || v == 2):
+			size = 2 + 3*int(v)
+		}
+		if n++; n == size {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// DecodeConsumed is like Decode, but also returns the number of bytes of src
+// that were consumed.
+func DecodeConsumed(dst, src []byte) (nDst, nSrc int, err error) {
+	return StdEncoding.DecodeConsumed(dst, src)
+}
+
+// DecodeStep decodes at most maxQuanta quanta from src into dst, allowing
+// large buffers to be decoded in bounded increments. It returns the number of
+// bytes written to dst, the number of bytes consumed from src, and whether
+// decoding is done, either because terminal padding was reached or because
+// src was exhausted. The caller advances src by nSrc and calls DecodeStep
+// again until done is true. Errors are reported exactly as Decode would
+// report them, with offsets relative to src. maxQuanta must be positive.
+func DecodeStep(dst, src []byte, maxQuanta int) (nDst, nSrc int, done bool, err error) {
+	if maxQuanta > len(src)/8-1 {
+		// This is the final step; any trailing fringe is left to decode to
+		// report.
+		var end bool
+		nDst, end, err = StdEncoding.decode(dst, src)
+		if err != nil {
+			return nDst, nDst / 3 * 8, false, err
+		}
+		nSrc = (nDst + 2) / 3 * 8
+		if nSrc > len(src) {
+			nSrc = len(src)
+		}
+		return nDst, nSrc, end || nSrc == len(src), nil
+	}
+
+	// At least one complete quantum follows this step, so the quanta in
+	// this step may not contain padding.
+	nSrc = maxQuanta * 8
+	for i, c := range src[0:nSrc] {
+		if c-'0' > 7 {
+			nDst, _, _ = StdEncoding.decode(dst, src[0:i/8*8])
+			return nDst, i / 8 * 8, false, StdEncoding.invalidSymbol(i, c)
+		}
+	}
+	nDst, _, _ = StdEncoding.decode(dst, src[0:nSrc])
+	return nDst, nSrc, false, nil
+}
+
 // DecodeString returns the bytes represented by the base8 string s.
+func (enc *Encoding) DecodeString(s string) ([]byte, error) {
+	dbuf := make([]byte, enc.DecodedLen(len(s)))
+	n, _, err := decode(enc, dbuf, s)
+	return dbuf[:n], err
+}
+
+// DecodeString returns the bytes represented by the standard base8 string s.
 func DecodeString(s string) ([]byte, error) {
-	buf := []byte(s)
-	n, _, err := decode(buf, buf)
-	return buf[:n], err
+	return StdEncoding.DecodeString(s)
+}
+
+// DecodeStringInto decodes the base8 string s into dst and returns the number
+// of bytes written. dst must have room for DecodedLen(len(s)) bytes. Unlike
+// DecodeString, DecodeStringInto reads s directly and does not allocate, so
+// dst may come from a pool of reusable buffers. If s contains invalid base8
+// data, it returns the number of bytes successfully written and a
+// CorruptInputError.
+func (enc *Encoding) DecodeStringInto(dst []byte, s string) (int, error) {
+	n, _, err := decode(enc, dst, s)
+	return n, err
+}
+
+// DecodeStringInto decodes the standard base8 string s into dst and returns
+// the number of bytes written.
+func DecodeStringInto(dst []byte, s string) (int, error) {
+	return StdEncoding.DecodeStringInto(dst, s)
+}
+
+// MustDecodeString is like DecodeString but panics if s is not valid
+// base8. It simplifies safe initialization of global variables holding
+// known-good encoded data.
+func (enc *Encoding) MustDecodeString(s string) []byte {
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		panic("base8: DecodeString(" + strconv.Quote(s) + "): " + err.Error())
+	}
+	return b
+}
+
+// MustDecodeString is like DecodeString but panics if s is not valid
+// standard base8.
+func MustDecodeString(s string) []byte {
+	return StdEncoding.MustDecodeString(s)
+}
+
+// AppendDecode appends the base8 decoded src to dst
+// and returns the extended buffer.
+// If the input is malformed, it returns the partially decoded src and an error.
+func (enc *Encoding) AppendDecode(dst, src []byte) ([]byte, error) {
+	// Compute the output size without padding to avoid over allocating.
+	n := len(src)
+	for n > 0 && rune(src[n-1]) == enc.padChar {
+		n--
+	}
+	n = DecodedLenNoPad(n)
+
+	dst = slices.Grow(dst, n)
+	n, err := enc.Decode(dst[len(dst):][:n], src)
+	return dst[:len(dst)+n], err
+}
+
+// AppendDecode appends the standard base8 decoded src to dst
+// and returns the extended buffer.
+func AppendDecode(dst, src []byte) ([]byte, error) {
+	return StdEncoding.AppendDecode(dst, src)
+}
+
+// ErrPartialQuantum is returned by DecodeStringWholeQuanta when the length of
+// its input is not a multiple of 8.
+var ErrPartialQuantum = errors.New("base8: input is not a whole number of quanta")
+
+// DecodeStringWholeQuanta is like DecodeString, but first rejects any input
+// whose length is not a multiple of 8 with ErrPartialQuantum. This catches
+// truncated input before any decoding is done. For an encoding with a length
+// escape, whose escaped tail may be 5 bytes long, it is the same as
+// DecodeString.
+func (enc *Encoding) DecodeStringWholeQuanta(s string) ([]byte, error) {
+	if len(s)%8 != 0 && !enc.escape {
+		return nil, ErrPartialQuantum
+	}
+	return enc.DecodeString(s)
+}
+
+// DecodeStringWholeQuanta is like DecodeString, but first rejects any input
+// whose length is not a multiple of 8 with ErrPartialQuantum.
+func DecodeStringWholeQuanta(s string) ([]byte, error) {
+	return StdEncoding.DecodeStringWholeQuanta(s)
+}
+
+// Compare compares the base8 strings a and b in the order of the data they
+// encode, returning -1 if a's data sorts before b's, +1 if it sorts after,
+// and 0 if they are equal. Padding is ignored, so a padded string and its
+// unpadded form compare equal.
+//
+// For MSBFirst encodings without a length escape, Compare works on the
+// encoded strings directly and does not allocate. a and b must be canonical encodings, as produced by
+// Encode; otherwise the result is unspecified.
+func (enc *Encoding) Compare(a, b string) int {
+	if enc.lsb || enc.escape {
+		da, _ := enc.DecodeString(a)
+		db, _ := enc.DecodeString(b)
+		return bytes.Compare(da, db)
+	}
+
+	a, b = enc.trimPadding(a), enc.trimPadding(b)
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if x, y := enc.decodeMap[a[i]], enc.decodeMap[b[i]]; x != y {
+			if x < y {
+				return -1
+			}
+			return +1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return +1
+	}
+	return 0
+}
+
+// Compare compares the standard base8 strings a and b in the order of the
+// data they encode.
+func Compare(a, b string) int {
+	return StdEncoding.Compare(a, b)
+}
+
+// trimPadding returns s without any trailing padding characters.
+func (enc *Encoding) trimPadding(s string) string {
+	if enc.padChar == NoPadding {
+		return s
+	}
+	for len(s) > 0 && rune(s[len(s)-1]) == enc.padChar {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// SortKey returns a key for the base8 string s such that comparing two keys
+// with bytes.Compare orders them the same way as the data they encode. Keys
+// are normalized: encodings that differ only in the unused bits of their
+// final quantum produce identical keys.
+func SortKey(s string) ([]byte, error) {
+	return DecodeString(s)
 }
 
 type decoder struct {
 	err    error
+	enc    *Encoding
 	r      io.Reader
-	end    bool       // saw end of message
-	buf    [1024]byte // leftover input
+	end    bool   // saw end of message
+	buf    []byte // leftover input
 	nbuf   int
-	out    []byte // leftover decoded output
-	outbuf [1024 / 8 * 3]byte
+	offset int64           // offset of buf[0] in the input stream
+	ign    *ignoringReader // filters r if enc ignores characters
+	out    []byte          // leftover decoded output
+	outbuf []byte
+	bufs   *streamBuffers // pooled buffers backing buf and outbuf, if any
+}
+
+// acquire makes sure that the decoder has buffers, taking them from bufPool
+// if it has none. A decoder holds its buffers whenever d.err is nil.
+func (d *decoder) acquire() {
+	if d.buf == nil {
+		d.bufs = bufPool.Get().(*streamBuffers)
+		d.buf, d.outbuf = d.bufs.encoded[0:], d.bufs.decoded[0:]
+	}
 }
 
-func readEncodedData(r io.Reader, buf []byte, min int) (n int, err error) {
+// release returns the decoder's buffers to bufPool if they came from there.
+// It is called once the decoder has stopped with an error, including io.EOF,
+// and returned all its output; only Reset or UnmarshalBinary make it usable
+// again.
+func (d *decoder) release() {
+	if d.bufs != nil {
+		bufPool.Put(d.bufs)
+		d.bufs, d.buf, d.outbuf, d.out = nil, nil, nil, nil
+	}
+}
+
+func readEncodedData(r io.Reader, buf []byte, min int, expectsPadding bool) (n int, err error) {
 	for n < min && err == nil {
 		var nn int
 		nn, err = r.Read(buf[n:])
 		n += nn
 	}
 	// data was read, less than min bytes could be read
-	if n < min && n > 0 && err == io.EOF {
+	if n < min && n > 0 && err == io.EOF && expectsPadding {
 		err = io.ErrUnexpectedEOF
 	}
 	// no data was read, the buffer already contains some data
-	if min < 8 && n == 0 && err == io.EOF {
+	if min < 8 && n == 0 && err == io.EOF && expectsPadding {
 		err = io.ErrUnexpectedEOF
 	}
 	return
 }
 
+// A peeker is a reader with an internal buffer, such as a *bufio.Reader,
+// whose buffered data the decoder can decode in place.
+type peeker interface {
+	Buffered() int
+	Peek(n int) ([]byte, error)
+	Discard(n int) (discarded int, err error)
+}
+
 func (d *decoder) Read(p []byte) (n int, err error) {
+	n, err = d.read(p)
+	if err != nil {
+		d.release()
+	}
+	return n, err
+}
+
+// read implements Read without releasing the decoder's buffers, so that
+// WriteTo and ReadByte can read into d.outbuf.
+func (d *decoder) read(p []byte) (n int, err error) {
 	// Use leftover decoded output from last read.
 	if len(d.out) > 0 {
 		n = copy(p, d.out)
@@ -304,38 +1558,76 @@ func (d *decoder) Read(p []byte) (n int, err error) {
 		return 0, d.err
 	}
 
-	// Read a chunk.
-	nn := len(p) / 3 * 8
-	if nn < 8 {
-		nn = 8
-	}
-	if nn > len(d.buf) {
-		nn = len(d.buf)
-	}
+	// Read a chunk. The decoder reads as much input as its buffer holds,
+	// regardless of len(p), so that small reads by the caller do not each
+	// cost a read from d.r; whatever p cannot hold is kept in d.out.
+	var chunk []byte
+	pk, peek := d.r.(peeker)
+	if peek && d.nbuf == 0 && !d.end && pk.Buffered() >= 8 {
+		// Fast path: decode whole quanta directly from d.r's buffer rather
+		// than copying them into d.buf first.
+		chunk, _ = pk.Peek(min(pk.Buffered(), len(d.buf)) / 8 * 8)
+	} else {
+		peek = false
 
-	// Minimum amount of bytes that needs to be read each cycle
-	min := 8 - d.nbuf
-	nn, d.err = readEncodedData(d.r, d.buf[d.nbuf:nn], min)
-	d.nbuf += nn
-	if d.nbuf < min {
-		return 0, d.err
+		// Minimum amount of bytes that needs to be read each cycle
+		min := 8 - d.nbuf
+		expectsPadding := d.enc.padChar != NoPadding
+		var nn int
+		nn, d.err = readEncodedData(d.r, d.buf[d.nbuf:], min, expectsPadding)
+		d.nbuf += nn
+		if d.end && d.enc.strictEnd && d.nbuf > 0 {
+			off := d.offset
+			if d.ign != nil {
+				off = d.ign.offset(off)
+			}
+			d.err = trailing(0, d.buf[0]).shift(off)
+			return 0, d.err
+		}
+		if d.nbuf < min && (expectsPadding || d.nbuf == 0 || d.err != io.EOF) {
+			return 0, d.err
+		}
+
+		nr := d.nbuf / 8 * 8
+		if !expectsPadding && d.err == io.EOF {
+			// Without padding, the end of the input marks the final quantum.
+			nr = d.nbuf
+		}
+		chunk = d.buf[0:nr]
 	}
 
 	// Decode chunk into p, or d.out and then p if p is too small.
-	nr := d.nbuf / 8 * 8
-	nw := DecodedLen(d.nbuf)
+	nr := len(chunk)
+	nw := d.enc.DecodedLen(nr)
 
 	if nw > len(p) {
-		nw, d.end, err = decode(d.outbuf[0:], d.buf[0:nr])
+		nw, d.end, err = decodeQuanta(d.enc, d.outbuf[0:], chunk)
 		d.out = d.outbuf[0:nw]
 		n = copy(p, d.out)
 		d.out = d.out[n:]
 	} else {
-		n, d.end, err = decode(p, d.buf[0:nr])
+		n, d.end, err = decodeQuanta(d.enc, p, chunk)
+	}
+	if peek {
+		pk.Discard(nr)
+	} else {
+		d.nbuf -= nr
+		copy(d.buf[0:], d.buf[nr:nr+d.nbuf])
+	}
+
+	if err != nil {
+		// Report the offset from the start of the stream rather than from
+		// the start of this chunk.
+		e := err.(CorruptInputError)
+		off := d.offset + e.offset
+		if d.ign != nil {
+			off = d.ign.offset(off)
+		}
+		err = e.shift(off - e.offset)
 	}
-	d.nbuf -= nr
-	for i := 0; i < d.nbuf; i++ {
-		d.buf[i] = d.buf[i+nr]
+	d.offset += int64(nr)
+	if d.ign != nil {
+		d.ign.discard(d.offset)
 	}
 
 	if err != nil && (d.err == nil || d.err == io.EOF) {
@@ -353,13 +1645,212 @@ func (d *decoder) Read(p []byte) (n int, err error) {
 	return n, d.err
 }
 
-// NewDecoder constructs a new base32 stream decoder.
+// WriteTo decodes the remaining input and writes it to w until EOF or an
+// error occurs, decoding directly into the decoder's output buffer rather
+// than into an intermediate one. It implements io.WriterTo, so io.Copy uses
+// it automatically.
+func (d *decoder) WriteTo(w io.Writer) (n int64, err error) {
+	for {
+		if len(d.out) == 0 {
+			// The output buffer holds everything decoded from a full input
+			// buffer, so Read decodes directly into it.
+			var nr int
+			nr, err = d.read(d.outbuf)
+			d.out = d.outbuf[0:nr]
+		}
+		if len(d.out) > 0 {
+			nw, werr := w.Write(d.out)
+			n += int64(nw)
+			d.out = d.out[nw:]
+			if werr == nil && len(d.out) > 0 {
+				werr = io.ErrShortWrite
+			}
+			if werr != nil {
+				return n, werr
+			}
+		}
+		if err != nil {
+			d.release()
+			if err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+	}
+}
+
+// ReadByte reads and returns the next decoded byte. It implements
+// io.ByteReader. When no decoded output is left over, it decodes a full
+// buffer at once so that subsequent calls are cheap.
+func (d *decoder) ReadByte() (byte, error) {
+	for len(d.out) == 0 {
+		n, err := d.read(d.outbuf)
+		d.out = d.outbuf[0:n]
+		if n == 0 && err != nil {
+			d.release()
+			return 0, err
+		}
+	}
+	b := d.out[0]
+	d.out = d.out[1:]
+	return b, nil
+}
+
+// Err returns the error, if any, that stopped the decoder: a
+// CorruptInputError if the input was invalid, or the error returned by the
+// underlying reader. Reaching the end of valid input is not an error, so Err
+// then returns nil. The error is reported by Err as soon as it occurs, even
+// while Read is still returning the data decoded before it.
+func (d *decoder) Err() error {
+	if d.err == io.EOF {
+		return nil
+	}
+	return d.err
+}
+
+// Buffered returns the number of bytes the decoder has read from its
+// underlying reader but not yet decoded, and the number of decoded bytes not
+// yet returned to the caller. Only when both are zero has every byte read from
+// the underlying reader been consumed by the caller.
+func (d *decoder) Buffered() (input, output int) {
+	return d.nbuf, len(d.out)
+}
+
+// Reset discards any buffered data and error state and makes the decoder
+// read from r, so that a decoder can be reused without allocating a new one.
+func (d *decoder) Reset(r io.Reader) {
+	if d.ign != nil {
+		*d.ign = ignoringReader{enc: d.enc, r: r, runs: d.ign.runs[:0]}
+		r = d.ign
+	}
+	d.acquire()
+	d.err = nil
+	d.r = r
+	d.end = false
+	d.nbuf = 0
+	d.offset = 0
+	d.out = nil
+}
+
+// NewDecoder constructs a new base8 stream decoder. The offset of a
+// CorruptInputError it returns is counted from the start of the stream. If
+// enc uses NoPadding, the end of the input ends the final quantum, which may
+// then hold 3 or 6 digits rather than 8. The
+// returned decoder implements io.WriterTo, io.ByteReader,
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler. It also has a
+// Buffered() (input, output int) method that reports how much data it holds,
+// a Reset(io.Reader) method that prepares it for reuse with a new reader, and
+// an Err() error method that reports why it stopped.
+//
+// The decoder reads ahead as much input as its buffer holds, so that small
+// reads from it do not each cost a read from r. If r has Buffered, Peek and
+// Discard methods, as *bufio.Reader does, data already buffered by r is
+// decoded in place; wrapping a source that returns only a few bytes per read,
+// such as a net.Conn, in a bufio.Reader lets it decode at bulk speed.
+//
+// The decoder's buffers are shared through an internal pool: they are returned
+// once Read has returned an error, including io.EOF, and taken again by Reset.
+func (enc *Encoding) NewDecoder(r io.Reader) io.Reader {
+	return enc.NewDecoderSize(r, defaultBufSize)
+}
+
+// NewDecoder constructs a new base8 stream decoder that uses the standard
+// encoding.
 func NewDecoder(r io.Reader) io.Reader {
-	return &decoder{r: r}
+	return StdEncoding.NewDecoder(r)
+}
+
+// NewDecoderSize is like NewDecoder, but the returned decoder reads up to
+// size bytes of encoded input from r at a time instead of the default 1024.
+// size is rounded down to a multiple of 8, and values below 8 are treated
+// as 8.
+func (enc *Encoding) NewDecoderSize(r io.Reader, size int) io.Reader {
+	if enc.escape {
+		panic("stream decoding with a length escape")
+	}
+	d := &decoder{enc: enc, r: r}
+	if size = streamBufSize(size); size != defaultBufSize {
+		// Only buffers of the default size are pooled; see acquire.
+		d.buf, d.outbuf = make([]byte, size), make([]byte, size/8*3)
+	}
+	d.acquire()
+	if enc.ignores {
+		d.ign = &ignoringReader{enc: enc, r: r}
+		d.r = d.ign
+	}
+	return d
+}
+
+// NewDecoderSize is like NewDecoder, but the returned decoder uses an input
+// buffer of the given size.
+func NewDecoderSize(r io.Reader, size int) io.Reader {
+	return StdEncoding.NewDecoderSize(r, size)
+}
+
+// DecodeAll decodes the base8 stream read from r until EOF and returns the
+// decoded data. sizeHint is the expected decoded length and is used to size
+// the initial buffer; the buffer grows as needed if the hint is too small.
+func DecodeAll(r io.Reader, sizeHint int) ([]byte, error) {
+	if sizeHint < 512 {
+		sizeHint = 512
+	}
+	buf := make([]byte, 0, sizeHint)
+	d := NewDecoder(r)
+	for {
+		if len(buf) == cap(buf) {
+			buf = append(buf, 0)[:len(buf)]
+		}
+		n, err := d.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return buf, err
+		}
+	}
 }
 
 // DecodedLen returns the maximum length in bytes of the decoded data
-// corresponding to n bytes of base32-encoded data.
+// corresponding to n bytes of base8-encoded data.
+func (enc *Encoding) DecodedLen(n int) int {
+	switch {
+	case enc.padChar == NoPadding:
+		return DecodedLenNoPad(n)
+	case enc.escape:
+		return n/8*3 + n%8/5
+	}
+	return n / 8 * 3
+}
+
+// DecodedLen returns the maximum length in bytes of the decoded data
+// corresponding to n bytes of standard base8-encoded data.
 func DecodedLen(n int) int {
+	return StdEncoding.DecodedLen(n)
+}
+
+// DecodedLen64 is like DecodedLen, but operates on int64 lengths.
+func (enc *Encoding) DecodedLen64(n int64) int64 {
+	switch {
+	case enc.padChar == NoPadding:
+		return n/8*3 + n%8*3/8
+	case enc.escape:
+		return n/8*3 + n%8/5
+	}
 	return n / 8 * 3
 }
+
+// DecodedLen64 returns the maximum length in bytes of the decoded data
+// corresponding to n bytes of standard base8-encoded data.
+func DecodedLen64(n int64) int64 {
+	return StdEncoding.DecodedLen64(n)
+}
+
+// DecodedLenNoPad returns the length in bytes of the decoded data
+// corresponding to n bytes of unpadded base8-encoded data. Unlike
+// DecodedLen, the result is exact for valid input lengths, including those
+// that end with a partial quantum of 3 or 6 digits. This is the value of
+// DecodedLen for encodings that use NoPadding.
+func DecodedLenNoPad(n int) int {
+	return n/8*3 + n%8*3/8
+}
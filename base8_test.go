@@ -1,11 +1,18 @@
 package base8
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/rand"
+	"slices"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 type testpair struct {
@@ -38,6 +45,16 @@ var bigtest = testpair{
 	"2507354134620142344645543306454713020141334620403506414510071554322721503622016433673145346=====",
 }
 
+// corruptOffset returns the offset of the CorruptInputError in err's chain,
+// or -1 if there is none.
+func corruptOffset(err error) int64 {
+	var cie CorruptInputError
+	if !errors.As(err, &cie) {
+		return -1
+	}
+	return cie.Offset()
+}
+
 func testEqual(t *testing.T, msg string, args ...interface{}) bool {
 	t.Helper()
 	if args[len(args)-2] != args[len(args)-1] {
@@ -84,10 +101,322 @@ func TestEncoderBuffering(t *testing.T) {
 	}
 }
 
+func TestEncoderFlush(t *testing.T) {
+	type flusher interface {
+		io.WriteCloser
+		Flush() error
+	}
+	for _, tc := range []struct {
+		enc    *Encoding
+		writes []string
+		want   []string // output after each write and flush
+	}{
+		{StdEncoding, []string{"f", "oo", "foob"}, []string{"314=====", "314=====336674==", "314=====336674==31467557304====="}},
+		{StdEncoding, []string{"foo", "", "bar"}, []string{"31467557", "31467557", "3146755730460562"}},
+		{RawEncoding, []string{"f", "oo"}, []string{"", "31467557"}},
+	} {
+		bb := &bytes.Buffer{}
+		w := tc.enc.NewEncoder(bb).(flusher)
+		var input string
+		var messages []string // separately padded messages
+		start := 0
+		for i, s := range tc.writes {
+			input += s
+			w.Write([]byte(s))
+			err := w.Flush()
+			testEqual(t, "Flush after %q gave error %v, want %v", input, err, error(nil))
+			testEqual(t, "Flush after %q wrote %q, want %q", input, bb.String(), tc.want[i])
+			if strings.HasSuffix(bb.String(), "=") {
+				messages = append(messages, bb.String()[start:])
+				start = bb.Len()
+			}
+		}
+		w.Close()
+		messages = append(messages, bb.String()[start:])
+
+		var got []byte
+		for _, m := range messages {
+			dbuf, err := tc.enc.DecodeString(m)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", m, err, error(nil))
+			got = append(got, dbuf...)
+		}
+		testEqual(t, "Decode(%q) = %q, want %q", bb.String(), string(got), input)
+	}
+}
+
+func TestEncoderWriteString(t *testing.T) {
+	input := bigtest.decoded
+	for bs := 1; bs <= 12; bs++ {
+		bb := &bytes.Buffer{}
+		encoder := NewEncoder(bb)
+		for pos := 0; pos < len(input); pos += bs {
+			end := min(pos+bs, len(input))
+			n, err := io.WriteString(encoder, input[pos:end])
+			testEqual(t, "WriteString(%q) gave error %v, want %v", input[pos:end], err, error(nil))
+			testEqual(t, "WriteString(%q) gave length %v, want %v", input[pos:end], n, end-pos)
+		}
+		encoder.Close()
+		testEqual(t, "Encoding/%d of %q = %q, want %q", bs, input, bb.String(), bigtest.encoded)
+	}
+
+	w := NewEncoder(io.Discard).(io.StringWriter)
+	data := strings.Repeat("x", 4096)
+	allocs := testing.AllocsPerRun(100, func() {
+		w.WriteString(data)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteString allocated %v times, want 0", allocs)
+	}
+}
+
+func TestEncoderReadFrom(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 100)
+	readers := map[string]func(io.Reader) io.Reader{
+		"plain":   func(r io.Reader) io.Reader { return r },
+		"onebyte": iotest.OneByteReader,
+		"half":    iotest.HalfReader,
+		"dataerr": iotest.DataErrReader,
+	}
+	for name, wrap := range readers {
+		for _, size := range []int{8, 16, 1024} {
+			for _, prefix := range []int{0, 1, 2, 4} {
+				bb := &bytes.Buffer{}
+				w := NewEncoderSize(bb, size)
+				w.Write(input[:prefix])
+				n, err := io.Copy(w, wrap(bytes.NewReader(input[prefix:])))
+				testEqual(t, "io.Copy/%s/%d/%d gave error %v, want %v", name, size, prefix, err, error(nil))
+				testEqual(t, "io.Copy/%s/%d/%d copied %v bytes, want %v", name, size, prefix, n, int64(len(input)-prefix))
+				w.Close()
+				testEqual(t, "io.Copy/%s/%d/%d wrote %q, want %q", name, size, prefix, bb.String(), EncodeToString(input))
+			}
+		}
+	}
+
+	// Errors from the reader are returned once the data read so far has been
+	// encoded, and leave the encoder usable.
+	bb := &bytes.Buffer{}
+	w := NewEncoder(bb)
+	_, err := w.(io.ReaderFrom).ReadFrom(iotest.TimeoutReader(strings.NewReader("foob")))
+	testEqual(t, "ReadFrom gave error %v, want %v", err, iotest.ErrTimeout)
+	testEqual(t, "ReadFrom wrote %q, want %q", bb.String(), "31467557")
+	w.Close()
+	testEqual(t, "Close wrote %q, want %q", bb.String(), "31467557304=====")
+
+	// Errors from the writer are sticky.
+	w = NewEncoder(errWriter{io.ErrShortWrite})
+	_, err = io.Copy(w, strings.NewReader("foobar"))
+	testEqual(t, "io.Copy gave error %v, want %v", err, io.ErrShortWrite)
+	_, err = io.Copy(w, strings.NewReader("foobar"))
+	testEqual(t, "io.Copy gave error %v, want %v", err, io.ErrShortWrite)
+}
+
+func TestEncoderReset(t *testing.T) {
+	type resetter interface {
+		io.WriteCloser
+		Reset(io.Writer)
+	}
+	w := NewEncoder(errWriter{io.ErrShortWrite}).(resetter)
+	w.Write([]byte("foobar"))
+	if err := w.Close(); err == nil {
+		t.Fatalf("Close on failing writer gave no error")
+	}
+	for _, p := range pairs {
+		// Leave a partial block behind, which Reset must discard.
+		w.Write([]byte("x"))
+		bb := &bytes.Buffer{}
+		w.Reset(bb)
+		w.Write([]byte(p.decoded))
+		err := w.Close()
+		testEqual(t, "Close gave error %v, want %v", err, error(nil))
+		testEqual(t, "Encode(%q) = %q, want %q", p.decoded, bb.String(), p.encoded)
+	}
+}
+
+func TestDecoderWriteTo(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 100)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		encoded := enc.EncodeToString(input)
+		for _, size := range []int{8, 16, 1024} {
+			for _, prefix := range []int{0, 1, 5} {
+				r := enc.NewDecoderSize(iotest.HalfReader(strings.NewReader(encoded)), size)
+				head := make([]byte, prefix)
+				io.ReadFull(r, head)
+				bb := &bytes.Buffer{}
+				n, err := r.(io.WriterTo).WriteTo(bb)
+				testEqual(t, "WriteTo/%d/%d gave error %v, want %v", size, prefix, err, error(nil))
+				testEqual(t, "WriteTo/%d/%d wrote %v bytes, want %v", size, prefix, n, int64(len(input)-prefix))
+				testEqual(t, "WriteTo/%d/%d = %q, want %q", size, prefix, string(head)+bb.String(), string(input))
+			}
+		}
+	}
+
+	// Data decoded before an error is written before the error is returned.
+	bb := &bytes.Buffer{}
+	_, err := io.Copy(bb, NewDecoder(strings.NewReader("3146755731=")))
+	testEqual(t, "io.Copy gave error %v, want %v", err, error(io.ErrUnexpectedEOF))
+	testEqual(t, "io.Copy wrote %q, want %q", bb.String(), "foo")
+
+	_, err = io.Copy(errWriter{io.ErrShortWrite}, NewDecoder(strings.NewReader("31467557")))
+	testEqual(t, "io.Copy gave error %v, want %v", err, io.ErrShortWrite)
+}
+
+func TestDecoderReadByte(t *testing.T) {
+	var raw []byte
+	for _, v := range []uint64{0, 1, 300, 1 << 40} {
+		raw = binary.AppendUvarint(raw, v)
+	}
+	raw = append(raw, "tail"...)
+	r := NewDecoderSize(strings.NewReader(EncodeToString(raw)), 8)
+	br := r.(io.ByteReader)
+	for _, want := range []uint64{0, 1, 300, 1 << 40} {
+		got, err := binary.ReadUvarint(br)
+		testEqual(t, "ReadUvarint gave error %v, want %v", err, error(nil))
+		testEqual(t, "ReadUvarint = %v, want %v", got, want)
+	}
+
+	// ReadByte and Read share the decoded output.
+	b, err := br.ReadByte()
+	testEqual(t, "ReadByte gave error %v, want %v", err, error(nil))
+	testEqual(t, "ReadByte = %q, want %q", b, byte('t'))
+	rest, err := io.ReadAll(r)
+	testEqual(t, "ReadAll gave error %v, want %v", err, error(nil))
+	testEqual(t, "ReadAll = %q, want %q", string(rest), "ail")
+	_, err = br.ReadByte()
+	testEqual(t, "ReadByte at end gave error %v, want %v", err, io.EOF)
+
+	br = NewDecoder(strings.NewReader("31467557=")).(io.ByteReader)
+	for range 3 {
+		br.ReadByte()
+	}
+	_, err = br.ReadByte()
+	testEqual(t, "ReadByte gave error %v, want %v", err, io.ErrUnexpectedEOF)
+}
+
+func TestDecoderBuffered(t *testing.T) {
+	type buffered interface {
+		io.Reader
+		Buffered() (input, output int)
+	}
+	for _, tc := range []struct {
+		input     string
+		read      int
+		nin, nout int
+	}{
+		{"", 1, 0, 0},
+		{"3146755730460562", 1, 0, 5},
+		{"3146755730460562", 6, 0, 0},
+		{"31467557304", 6, 3, 0},
+		{"3146755730460562314", 9, 3, 0},
+	} {
+		r := NewDecoder(strings.NewReader(tc.input)).(buffered)
+		r.Read(make([]byte, tc.read))
+		nin, nout := r.Buffered()
+		testEqual(t, "Buffered after reading %d of %q gave input %d, want %d", tc.read, tc.input, nin, tc.nin)
+		testEqual(t, "Buffered after reading %d of %q gave output %d, want %d", tc.read, tc.input, nout, tc.nout)
+	}
+}
+
+func TestStreamErr(t *testing.T) {
+	type errer interface {
+		Err() error
+	}
+
+	want := errors.New("sink failed")
+	w := NewEncoder(errWriter{want})
+	testEqual(t, "encoder Err() = %v, want %v", w.(errer).Err(), error(nil))
+	io.Copy(w, strings.NewReader("foobar"))
+	testEqual(t, "encoder Err() after failed copy = %v, want %v", w.(errer).Err(), want)
+
+	for _, tc := range []struct {
+		r       io.Reader
+		corrupt bool
+		err     error
+	}{
+		{strings.NewReader("3146755730460562"), false, nil},
+		{strings.NewReader("31467557304x0562"), true, nil},
+		{iotest.ErrReader(want), false, want},
+	} {
+		r := NewDecoder(tc.r)
+		io.Copy(io.Discard, r)
+		err := r.(errer).Err()
+		if _, ok := err.(CorruptInputError); ok != tc.corrupt || (!tc.corrupt && err != tc.err) {
+			t.Errorf("decoder Err() = %v, want corrupt %v or %v", err, tc.corrupt, tc.err)
+		}
+	}
+}
+
+func TestDecoderReset(t *testing.T) {
+	type resetter interface {
+		io.Reader
+		Reset(io.Reader)
+	}
+	r := NewDecoder(strings.NewReader("31=")).(resetter)
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatalf("ReadAll(%q) gave no error", "31=")
+	}
+	for _, p := range pairs {
+		// Leave buffered input and output behind, which Reset must discard.
+		r.Read(make([]byte, 1))
+		r.Reset(strings.NewReader(p.encoded))
+		got, err := io.ReadAll(r)
+		testEqual(t, "ReadAll(%q) = error %v, want %v", p.encoded, err, error(nil))
+		testEqual(t, "ReadAll(%q) = %q, want %q", p.encoded, string(got), p.decoded)
+	}
+}
+
+// maxWriter records the size of the largest write made to it.
+type maxWriter struct {
+	bytes.Buffer
+	max int
+}
+
+func (w *maxWriter) Write(p []byte) (int, error) {
+	w.max = max(w.max, len(p))
+	return w.Buffer.Write(p)
+}
+
+// maxReader records the size of the largest read made from it.
+type maxReader struct {
+	r   io.Reader
+	max int
+}
+
+func (r *maxReader) Read(p []byte) (int, error) {
+	r.max = max(r.max, len(p))
+	return r.r.Read(p)
+}
+
+func TestStreamSize(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 100)
+	want := StdEncoding.EncodeToString(input)
+	for _, tc := range []struct{ size, limit int }{{0, 8}, {8, 8}, {13, 8}, {100, 96}, {64 << 10, 64 << 10}} {
+		w := &maxWriter{}
+		e := NewEncoderSize(w, tc.size)
+		e.Write(input)
+		e.Close()
+		testEqual(t, "NewEncoderSize(%d) wrote %q, want %q", tc.size, w.String(), want)
+		if w.max > tc.limit {
+			t.Errorf("NewEncoderSize(%d) made a %d-byte write, want at most %d", tc.size, w.max, tc.limit)
+		}
+
+		r := &maxReader{r: strings.NewReader(want)}
+		got, err := io.ReadAll(NewDecoderSize(r, tc.size))
+		testEqual(t, "NewDecoderSize(%d) = error %v, want %v", tc.size, err, error(nil))
+		testEqual(t, "NewDecoderSize(%d) = %q, want %q", tc.size, string(got), string(input))
+		if r.max > tc.limit {
+			t.Errorf("NewDecoderSize(%d) made a %d-byte read, want at most %d", tc.size, r.max, tc.limit)
+		}
+
+		got, err = io.ReadAll(RawEncoding.NewDecoderSize(strings.NewReader(RawEncoding.EncodeToString(input)), tc.size))
+		testEqual(t, "RawEncoding.NewDecoderSize(%d) = error %v, want %v", tc.size, err, error(nil))
+		testEqual(t, "RawEncoding.NewDecoderSize(%d) = %q, want %q", tc.size, string(got), string(input))
+	}
+}
+
 func TestDecode(t *testing.T) {
 	for _, p := range pairs {
 		dbuf := make([]byte, DecodedLen(len(p.encoded)))
-		count, end, err := decode(dbuf, []byte(p.encoded))
+		count, end, err := StdEncoding.decode(dbuf, []byte(p.encoded))
 		testEqual(t, "Decode(%q) = error %v, want %v", p.encoded, err, error(nil))
 		testEqual(t, "Decode(%q) = length %v, want %v", p.encoded, count, len(p.decoded))
 		if len(p.encoded) > 0 {
@@ -170,6 +499,21 @@ func TestDecoderError(t *testing.T) {
 	}
 }
 
+func TestDecoderErrorOffset(t *testing.T) {
+	encoded := []byte(EncodeToString(bytes.Repeat([]byte("foobar"), 1000)))
+	for _, off := range []int{0, 7, 8, 1023, 1024, 5001, len(encoded) - 1} {
+		input := slices.Clone(encoded)
+		input[off] = '8'
+		for _, bs := range []int{1, 3, 100, 4096} {
+			for _, size := range []int{8, 1024} {
+				r := NewDecoderSize(strings.NewReader(string(input)), size)
+				_, err := io.CopyBuffer(io.Discard, struct{ io.Reader }{r}, make([]byte, bs))
+				testEqual(t, "Corruption at %d, read size %d, buffer size %d, reported at %v, want %v", off, bs, size, corruptOffset(err), int64(off))
+			}
+		}
+	}
+}
+
 // TestReaderEOF ensures decoder.Read behaves correctly when input data is
 // exhausted.
 func TestReaderEOF(t *testing.T) {
@@ -207,6 +551,45 @@ func TestDecoderBuffering(t *testing.T) {
 	}
 }
 
+type countingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.r.Read(p)
+}
+
+func TestDecoderReadAhead(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 100)
+	encoded := EncodeToString(input)
+
+	// Reading one byte at a time must not read from the source once per
+	// quantum.
+	cr := &countingReader{r: strings.NewReader(encoded)}
+	got, err := io.ReadAll(iotest.OneByteReader(NewDecoder(cr)))
+	testEqual(t, "NewDecoder read bytewise = error %v, want %v", err, error(nil))
+	testEqual(t, "NewDecoder read bytewise = %q, want %q", string(got), string(input))
+	if want := len(encoded)/1024 + 2; cr.reads > want {
+		t.Errorf("NewDecoder read bytewise made %d reads from its source, want at most %d", cr.reads, want)
+	}
+
+	// A bufio.Reader source is decoded in place, whatever the size of its
+	// reads from the underlying source.
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		encoded := enc.EncodeToString(input)
+		got, err := io.ReadAll(enc.NewDecoder(bufio.NewReader(iotest.HalfReader(strings.NewReader(encoded)))))
+		testEqual(t, "NewDecoder(bufio.Reader) = error %v, want %v", err, error(nil))
+		testEqual(t, "NewDecoder(bufio.Reader) = %q, want %q", string(got), string(input))
+	}
+
+	corrupt := []byte(encoded)
+	corrupt[5000] = 'x'
+	_, err = io.ReadAll(NewDecoder(bufio.NewReader(bytes.NewReader(corrupt))))
+	testEqual(t, "NewDecoder(bufio.Reader) corrupt at offset %d, want %d", corruptOffset(err), int64(5000))
+}
+
 func TestDecodeCorrupt(t *testing.T) {
 	testCases := []struct {
 		input  string
@@ -235,6 +618,13 @@ func TestDecodeCorrupt(t *testing.T) {
 		{"111111==", -1},
 		{"1111111=", 7},
 		{"11111111", -1},
+		{"31467557!1111111", 8},
+		{"3146755731467!57", 13},
+		{"314675573146755/", 15},
+		{"3146755731467558", 15},
+		{"314675573146755:", 15},
+		{"31467557314=====", -1},
+		{"31467557314=====31467557", 11},
 	}
 	for _, tc := range testCases {
 		dbuf := make([]byte, DecodedLen(len(tc.input)))
@@ -247,13 +637,121 @@ func TestDecodeCorrupt(t *testing.T) {
 		}
 		switch err := err.(type) {
 		case CorruptInputError:
-			testEqual(t, "Corruption in %q at offset %v, want %v", tc.input, int(err), tc.offset)
+			testEqual(t, "Corruption in %q at offset %v, want %v", tc.input, int(err.Offset()), tc.offset)
 		default:
 			t.Error("Decoder failed to detect corruption in", tc)
 		}
 	}
 }
 
+// referenceDecodeDigits packs the 3-bit values of digits MSB-first into
+// bytes, discarding any leftover bits.
+func referenceDecodeDigits(digits string) []byte {
+	var out []byte
+	var acc, nbits uint
+	for i := 0; i < len(digits); i++ {
+		acc = acc<<3 | uint(digits[i]-'0')
+		nbits += 3
+		if nbits >= 8 {
+			nbits -= 8
+			out = append(out, byte(acc>>nbits))
+		}
+	}
+	return out
+}
+
+// TestDecodeFinalQuantum exhaustively checks decode's verdict for every
+// combination of final-quantum digit count and padding count. A final
+// quantum is valid if it is complete (8 digits, no padding) or if it holds
+// 3 or 6 digits followed by enough padding to fill the quantum. Padding
+// beyond the end of the quantum is trailing data, which Decode ignores.
+func TestDecodeFinalQuantum(t *testing.T) {
+	const digits = "12345670"
+	for _, prefix := range []string{"", "31467557", "3146755730460562"} {
+		for d := 1; d <= 8; d++ {
+			for p := 0; p <= 7; p++ {
+				input := prefix + digits[:d] + strings.Repeat("=", p)
+				valid := d == 8 && p == 0 || (d == 3 || d == 6) && p >= 8-d
+
+				dbuf := make([]byte, DecodedLen(len(input))+3)
+				n, end, err := StdEncoding.decode(dbuf, []byte(input))
+				if !valid {
+					if _, ok := err.(CorruptInputError); !ok {
+						t.Errorf("StdEncoding.decode(%q) = %v, want CorruptInputError", input, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("StdEncoding.decode(%q) = %v, want success", input, err)
+					continue
+				}
+				want := referenceDecodeDigits(prefix + digits[:d])
+				testEqual(t, "StdEncoding.decode(%q) = %q, want %q", input, string(dbuf[:n]), string(want))
+				testEqual(t, "StdEncoding.decode(%q) = end %v, want %v", input, end, p > 0)
+			}
+		}
+	}
+}
+
+func decodeInSteps(t *testing.T, src []byte, maxQuanta int) ([]byte, error) {
+	t.Helper()
+	dst := make([]byte, DecodedLen(len(src))+3)
+	var nDst, nSrc int
+	for steps := 0; ; steps++ {
+		if steps > len(src)+1 {
+			t.Fatalf("DecodeStep(%q, %d) did not terminate", src, maxQuanta)
+		}
+		nd, ns, done, err := DecodeStep(dst[nDst:], src[nSrc:], maxQuanta)
+		if ns > maxQuanta*8 && !done && err == nil {
+			t.Fatalf("DecodeStep consumed %d bytes, want at most %d", ns, maxQuanta*8)
+		}
+		nDst += nd
+		if err != nil {
+			return dst[:nDst], err.(CorruptInputError).shift(int64(nSrc))
+		}
+		nSrc += ns
+		if done {
+			return dst[:nDst], nil
+		}
+	}
+}
+
+func TestDecodeStep(t *testing.T) {
+	n := 3*1000 + 1
+	raw := make([]byte, n)
+	for i := range raw {
+		raw[i] = byte(i * 7)
+	}
+	encoded := []byte(EncodeToString(raw))
+
+	for _, maxQuanta := range []int{1, 2, 7, 1000, 2000} {
+		decoded, err := decodeInSteps(t, encoded, maxQuanta)
+		if err != nil {
+			t.Fatalf("DecodeStep/%d failed: %v", maxQuanta, err)
+		}
+		if !bytes.Equal(decoded, raw) {
+			t.Errorf("DecodeStep/%d produced different output than Decode", maxQuanta)
+		}
+	}
+
+	for _, input := range []string{
+		"",
+		"31467557",
+		"31467557314=====",
+		"31467557314=====31467557",
+		"314=====31467557",
+		"3146755731467!57",
+		"314675573146755",
+		"31467557314675573146=557",
+	} {
+		dbuf := make([]byte, DecodedLen(len(input))+3)
+		n, wantErr := Decode(dbuf, []byte(input))
+		got, err := decodeInSteps(t, []byte(input), 1)
+		testEqual(t, "DecodeStep(%q) = error %v, want %v", input, err, wantErr)
+		testEqual(t, "DecodeStep(%q) = %q, want %q", input, string(got), string(dbuf[:n]))
+	}
+}
+
 func TestBig(t *testing.T) {
 	n := 3*1000 + 1
 	raw := make([]byte, n)
@@ -287,6 +785,89 @@ func TestBig(t *testing.T) {
 	}
 }
 
+func TestDecodeAll(t *testing.T) {
+	for _, sizeHint := range []int{len(bigtest.decoded), 3, 0} {
+		decoded, err := DecodeAll(strings.NewReader(bigtest.encoded), sizeHint)
+		if err != nil {
+			t.Errorf("DecodeAll(%q, %d) failed: %v", bigtest.encoded, sizeHint, err)
+		}
+		testEqual(t, "DecodeAll(%q, %d) = %q, want %q", bigtest.encoded, sizeHint, string(decoded), bigtest.decoded)
+	}
+
+	big := strings.Repeat("31467557", 1000)
+	decoded, err := DecodeAll(strings.NewReader(big), 1)
+	if err != nil {
+		t.Errorf("DecodeAll(%d bytes, 1) failed: %v", len(big), err)
+	}
+	testEqual(t, "DecodeAll(%d bytes, 1) = %q, want %q", len(big), string(decoded), strings.Repeat("foo", 1000))
+
+	_, err = DecodeAll(strings.NewReader("01234568"), 0)
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("Corrupt input error expected.  Found %T (%v)", err, err)
+	}
+}
+
+func TestDecodeStringWholeQuanta(t *testing.T) {
+	for _, in := range []string{"31467557", "3146755730460562", "31467557314====="} {
+		got, err := DecodeStringWholeQuanta(in)
+		if err != nil {
+			t.Errorf("DecodeStringWholeQuanta(%q) failed: %v", in, err)
+		}
+		want, _ := DecodeString(in)
+		testEqual(t, "DecodeStringWholeQuanta(%q) = %q, want %q", in, string(got), string(want))
+	}
+	for _, in := range []string{"3146755", "314675573", "314====", "314675573146755"} {
+		_, err := DecodeStringWholeQuanta(in)
+		testEqual(t, "DecodeStringWholeQuanta(%q) = %v, want %v", in, err, ErrPartialQuantum)
+	}
+	_, err := DecodeStringWholeQuanta("3146755!")
+	testEqual(t, "DecodeStringWholeQuanta(%q) = %v, want %v", "3146755!", corruptOffset(err), int64(7))
+
+	// An escaped tail is complete at 5 bytes.
+	got, err := StdEncoding.WithLengthEscape().DecodeStringWholeQuanta("31467557=1304")
+	testEqual(t, "WithLengthEscape().DecodeStringWholeQuanta(%q) = error %v, want %v", "31467557=1304", err, error(nil))
+	testEqual(t, "WithLengthEscape().DecodeStringWholeQuanta(%q) = %q, want %q", "31467557=1304", string(got), "foob")
+}
+
+func TestSortKey(t *testing.T) {
+	// Non-canonical encodings differ from canonical ones only in the unused
+	// low bits of the final digit.
+	for _, tc := range []struct {
+		canonical, nonCanonical string
+	}{
+		{"314=====", "315====="},
+		{"314674==", "314677=="},
+	} {
+		want, err := SortKey(tc.canonical)
+		if err != nil {
+			t.Fatalf("SortKey(%q) failed: %v", tc.canonical, err)
+		}
+		got, err := SortKey(tc.nonCanonical)
+		if err != nil {
+			t.Errorf("SortKey(%q) failed: %v", tc.nonCanonical, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("SortKey(%q) = %q, want %q", tc.nonCanonical, got, want)
+		}
+	}
+
+	for _, a := range pairs {
+		for _, b := range pairs {
+			ka, _ := SortKey(a.encoded)
+			kb, _ := SortKey(b.encoded)
+			got := bytes.Compare(ka, kb)
+			want := strings.Compare(a.decoded, b.decoded)
+			if got != want {
+				t.Errorf("Compare(SortKey(%q), SortKey(%q)) = %d, want %d", a.encoded, b.encoded, got, want)
+			}
+		}
+	}
+
+	if _, err := SortKey("31!====="); err == nil {
+		t.Error("SortKey accepted corrupt input")
+	}
+}
+
 func testStringEncoding(t *testing.T, expected string, examples []string) {
 	for _, e := range examples {
 		buf, err := DecodeString(e)
@@ -309,6 +890,15 @@ func BenchmarkEncode(b *testing.B) {
 	}
 }
 
+func BenchmarkEncodeLetter(b *testing.B) {
+	data := make([]byte, 8192)
+	buf := make([]byte, LetterEncoding.EncodedLen(len(data)))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		LetterEncoding.Encode(buf, data)
+	}
+}
+
 func BenchmarkEncodeToString(b *testing.B) {
 	data := make([]byte, 8192)
 	b.SetBytes(int64(len(data)))
@@ -326,6 +916,16 @@ func BenchmarkDecode(b *testing.B) {
 		Decode(buf, data)
 	}
 }
+func BenchmarkDecodeLetter(b *testing.B) {
+	data := make([]byte, LetterEncoding.EncodedLen(8192))
+	LetterEncoding.Encode(data, make([]byte, 8192))
+	buf := make([]byte, 8192)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		LetterEncoding.Decode(buf, data)
+	}
+}
+
 func BenchmarkDecodeString(b *testing.B) {
 	data := EncodeToString(make([]byte, 8192))
 	b.SetBytes(int64(len(data)))
@@ -334,6 +934,39 @@ func BenchmarkDecodeString(b *testing.B) {
 	}
 }
 
+func BenchmarkDecodeStringLarge(b *testing.B) {
+	data := EncodeToString(make([]byte, 4<<20))
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DecodeString(data)
+	}
+}
+
+// TestDecodeInPlace verifies that decoding into the buffer that holds the
+// encoded input, as DecodeString does, produces correct output. Each
+// quantum is read before the (shorter) decoded quantum is written over it.
+func TestDecodeInPlace(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		buf := []byte(p.encoded)
+		n, err := Decode(buf, buf)
+		if err != nil {
+			t.Errorf("Decode(%q) in place failed: %v", p.encoded, err)
+			continue
+		}
+		testEqual(t, "Decode(%q) in place = %q, want %q", p.encoded, string(buf[:n]), p.decoded)
+	}
+
+	for _, in := range []string{"3", "31", "314====", "31467557!", "3146755731467!57"} {
+		want := make([]byte, DecodedLen(len(in))+3)
+		wantN, wantErr := Decode(want, []byte(in))
+		buf := []byte(in)
+		n, err := Decode(buf, buf)
+		testEqual(t, "Decode(%q) in place = error %v, want %v", in, err, wantErr)
+		testEqual(t, "Decode(%q) in place = %q, want %q", in, string(buf[:n]), string(want[:wantN]))
+	}
+}
+
 func TestDecodeWithPadding(t *testing.T) {
 	for _, pair := range pairs {
 
@@ -380,19 +1013,52 @@ func TestEncodedDecodedLen(t *testing.T) {
 	}
 }
 
+func TestSizeReport(t *testing.T) {
+	for _, tc := range []struct {
+		in, wantEnc, wantNoPad int
+	}{
+		{0, 0, 0},
+		{1, 8, 3},
+		{2, 8, 6},
+		{3, 8, 8},
+		{4, 16, 11},
+		{5, 16, 14},
+		{6, 16, 16},
+		{100, 272, 267},
+	} {
+		raw, enc, noPad := SizeReport(make([]byte, tc.in))
+		if raw != tc.in || enc != tc.wantEnc || noPad != tc.wantNoPad {
+			t.Errorf("SizeReport(%d bytes) = %d, %d, %d; want %d, %d, %d", tc.in, raw, enc, noPad, tc.in, tc.wantEnc, tc.wantNoPad)
+		}
+		if s := EncodeToString(make([]byte, tc.in)); len(strings.TrimRight(s, "=")) != noPad {
+			t.Errorf("SizeReport(%d bytes) unpadded length = %d, but encoded to %q", tc.in, noPad, s)
+		}
+	}
+}
+
 func TestWithoutPaddingClose(t *testing.T) {
-	for _, testpair := range pairs {
+	encodings := []*Encoding{
+		StdEncoding,
+		RawEncoding,
+	}
+	for _, encoding := range encodings {
+		for _, testpair := range pairs {
 
-		var buf bytes.Buffer
-		encoder := NewEncoder(&buf)
-		encoder.Write([]byte(testpair.decoded))
-		encoder.Close()
+			var buf bytes.Buffer
+			encoder := encoding.NewEncoder(&buf)
+			encoder.Write([]byte(testpair.decoded))
+			encoder.Close()
 
-		expected := testpair.encoded
-		res := buf.String()
+			expected := testpair.encoded
+			if encoding.padChar == NoPadding {
+				expected = strings.Replace(expected, "=", "", -1)
+			}
 
-		if res != expected {
-			t.Errorf("Expected %s got %s", expected, res)
+			res := buf.String()
+
+			if res != expected {
+				t.Errorf("Expected %s got %s; padChar=%d", expected, res, encoding.padChar)
+			}
 		}
 	}
 }
@@ -437,3 +1103,856 @@ func TestDecodeSmallBuffer(t *testing.T) {
 		}
 	}
 }
+
+func TestNewEncoding(t *testing.T) {
+	// "HIJKLMNO" permits the SWAR fast path; "abcdefgh" and "76543210" do
+	// not.
+	for _, alphabet := range []string{"HIJKLMNO", "abcdefgh", "76543210"} {
+		enc := NewEncoding(alphabet)
+		translate := func(s string) string {
+			return strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '7' {
+					return rune(alphabet[r-'0'])
+				}
+				return r
+			}, s)
+		}
+		for _, p := range append(pairs, bigtest) {
+			want := translate(p.encoded)
+			got := enc.EncodeToString([]byte(p.decoded))
+			testEqual(t, "Encode(%q) = %q, want %q", p.decoded, got, want)
+
+			dbuf, err := enc.DecodeString(want)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", want, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", want, string(dbuf), p.decoded)
+
+			bb := &bytes.Buffer{}
+			w := enc.NewEncoder(bb)
+			w.Write([]byte(p.decoded))
+			w.Close()
+			testEqual(t, "NewEncoder(%q) = %q, want %q", p.decoded, bb.String(), want)
+
+			dbuf, err = ioutil.ReadAll(enc.NewDecoder(strings.NewReader(want)))
+			testEqual(t, "NewDecoder(%q) = error %v, want %v", want, err, error(nil))
+			testEqual(t, "NewDecoder(%q) = %q, want %q", want, string(dbuf), p.decoded)
+		}
+
+		// Symbols from other alphabets are rejected.
+		input := translate("3146755") + "8"
+		want := CorruptInputError{offset: 7, b: '8', expected: ExpectSymbol, err: ErrInvalidCharacter}
+		if _, err := enc.DecodeString(input); err != want {
+			t.Errorf("DecodeString(%q) = %#v, want %#v", input, err, want)
+		}
+	}
+}
+
+func TestNewEncodingPanics(t *testing.T) {
+	for _, alphabet := range []string{
+		"0123456",
+		"012345678",
+		"0123456\n",
+		"0123456\r",
+		"0123456=",
+		"01234566",
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewEncoding(%q) did not panic", alphabet)
+				}
+			}()
+			NewEncoding(alphabet)
+		}()
+	}
+}
+
+func TestRawEncoding(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		want := strings.TrimRight(p.encoded, "=")
+		got := RawEncoding.EncodeToString([]byte(p.decoded))
+		testEqual(t, "RawEncoding.Encode(%q) = %q, want %q", p.decoded, got, want)
+		testEqual(t, "RawEncoding.EncodedLen(%d) = %d, want %d", len(p.decoded), RawEncoding.EncodedLen(len(p.decoded)), len(want))
+		testEqual(t, "RawEncoding.DecodedLen(%d) = %d, want %d", len(want), RawEncoding.DecodedLen(len(want)), len(p.decoded))
+
+		dbuf, err := RawEncoding.DecodeString(want)
+		testEqual(t, "RawEncoding.DecodeString(%q) = error %v, want %v", want, err, error(nil))
+		testEqual(t, "RawEncoding.DecodeString(%q) = %q, want %q", want, string(dbuf), p.decoded)
+
+		bb := &bytes.Buffer{}
+		w := RawEncoding.NewEncoder(bb)
+		w.Write([]byte(p.decoded))
+		w.Close()
+		testEqual(t, "RawEncoding.NewEncoder(%q) = %q, want %q", p.decoded, bb.String(), want)
+	}
+
+	for _, tc := range []struct {
+		input  string
+		offset int
+	}{
+		{"3", 0},
+		{"31", 0},
+		{"3146", 0},
+		{"31467", 0},
+		{"3146755", 0},
+		{"314675573", 8},
+		{"314=====", 3},
+		{"314\xff", 3},
+	} {
+		_, err := RawEncoding.DecodeString(tc.input)
+		if corruptOffset(err) != int64(tc.offset) {
+			t.Errorf("RawEncoding.DecodeString(%q) = %v, want offset %v", tc.input, err, tc.offset)
+		}
+	}
+}
+
+func TestWithPadding(t *testing.T) {
+	for _, padding := range []rune{'*', '.', '\xff', NoPadding, StdPadding} {
+		enc := StdEncoding.WithPadding(padding)
+		for _, p := range append(pairs, bigtest) {
+			want := p.encoded
+			if padding == NoPadding {
+				want = strings.TrimRight(want, "=")
+			} else {
+				want = strings.ReplaceAll(want, "=", string([]byte{byte(padding)}))
+			}
+			got := enc.EncodeToString([]byte(p.decoded))
+			testEqual(t, "Encode(%q) = %q, want %q", p.decoded, got, want)
+			testEqual(t, "EncodedLen(%d) = %d, want %d", len(p.decoded), enc.EncodedLen(len(p.decoded)), len(want))
+			if enc.DecodedLen(len(want)) < len(p.decoded) {
+				t.Errorf("DecodedLen(%d) = %d, want at least %d", len(want), enc.DecodedLen(len(want)), len(p.decoded))
+			}
+
+			dbuf, err := enc.DecodeString(want)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", want, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", want, string(dbuf), p.decoded)
+
+			bb := &bytes.Buffer{}
+			w := enc.NewEncoder(bb)
+			w.Write([]byte(p.decoded))
+			w.Close()
+			testEqual(t, "NewEncoder(%q) = %q, want %q", p.decoded, bb.String(), want)
+
+			for bs := 1; bs <= 16; bs++ {
+				r := enc.NewDecoder(iotest.OneByteReader(strings.NewReader(want)))
+				var got []byte
+				buf := make([]byte, bs)
+				for {
+					n, err := r.Read(buf)
+					got = append(got, buf[:n]...)
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("NewDecoder(%q)/%d: %v", want, bs, err)
+					}
+				}
+				testEqual(t, "NewDecoder(%q)/%d = %q, want %q", want, bs, string(got), p.decoded)
+			}
+		}
+	}
+}
+
+func TestWithPaddingMismatch(t *testing.T) {
+	tilde := StdEncoding.WithPadding('~')
+	for _, tc := range []struct {
+		enc   *Encoding
+		input string
+		err   CorruptInputError
+	}{
+		{tilde, "314=====", CorruptInputError{offset: 3, b: '=', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+		{tilde, "31467===", CorruptInputError{offset: 5, b: '=', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+		{tilde, "31467~~~", CorruptInputError{offset: 5, b: '~', expected: ExpectSymbol, err: ErrInvalidPadding}},
+		{StdEncoding, "314~~~~~", CorruptInputError{offset: 3, b: '~', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+		{RawEncoding, "314=====", CorruptInputError{offset: 3, b: '=', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+	} {
+		_, err := tc.enc.DecodeString(tc.input)
+		testEqual(t, "DecodeString(%q) = %#v, want %#v", tc.input, err, error(tc.err))
+		_, err = tc.enc.Validate([]byte(tc.input))
+		testEqual(t, "Validate(%q) = %#v, want %#v", tc.input, err, error(tc.err))
+	}
+}
+
+func TestDecodeMap(t *testing.T) {
+	for _, enc := range []*Encoding{
+		StdEncoding,
+		RawEncoding,
+		LetterEncoding.WithPadding('~'),
+		StdEncoding.WithPadding('~').WithPadding(StdPadding),
+		StdEncoding.WithIgnoredChars(" \n").WithPadding(NoPadding),
+	} {
+		for c := 0; c < 256; c++ {
+			want := byte(invalidIndex)
+			switch {
+			case strings.IndexByte(string(enc.encode[:]), byte(c)) >= 0:
+				want = byte(strings.IndexByte(string(enc.encode[:]), byte(c)))
+			case rune(c) == enc.padChar:
+				want = paddingIndex
+			case enc.ignores && (c == ' ' || c == '\n'):
+				want = ignoredIndex
+			}
+			testEqual(t, "decodeMap[%q] = %#x, want %#x", byte(c), enc.decodeMap[c], want)
+		}
+	}
+}
+
+func TestWithPaddingPanics(t *testing.T) {
+	for _, padding := range []rune{'0', '7', '\r', '\n', 0x100, -2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithPadding(%q) did not panic", padding)
+				}
+			}()
+			StdEncoding.WithPadding(padding)
+		}()
+	}
+}
+
+func TestRawDecoder(t *testing.T) {
+	readers := map[string]func(io.Reader) io.Reader{
+		"onebyte": iotest.OneByteReader,
+		"half":    iotest.HalfReader,
+		"dataerr": iotest.DataErrReader,
+	}
+	for _, p := range append(pairs, bigtest) {
+		encoded := strings.TrimRight(p.encoded, "=")
+		for name, wrap := range readers {
+			got, err := io.ReadAll(RawEncoding.NewDecoder(wrap(strings.NewReader(encoded))))
+			testEqual(t, "RawEncoding.NewDecoder(%s(%q)) = error %v, want %v", name, encoded, err, error(nil))
+			testEqual(t, "RawEncoding.NewDecoder(%s(%q)) = %q, want %q", name, encoded, string(got), p.decoded)
+		}
+	}
+}
+
+func TestRawDecoderCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"3146755", CorruptInputError{offset: 0, b: 0, expected: ExpectSymbol, err: ErrInvalidLength}},
+		{"314=====", CorruptInputError{offset: 3, b: '=', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+	} {
+		_, err := ioutil.ReadAll(RawEncoding.NewDecoder(strings.NewReader(tc.input)))
+		if err != tc.err {
+			t.Errorf("RawEncoding.NewDecoder(%q) = %v, want %v", tc.input, err, tc.err)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	prefix := []byte("prefix:")
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for _, p := range append(pairs, bigtest) {
+			encoded := enc.EncodeToString([]byte(p.decoded))
+
+			got := enc.AppendEncode(prefix[:len(prefix):len(prefix)], []byte(p.decoded))
+			testEqual(t, "AppendEncode(%q) = %q, want %q", p.decoded, string(got), string(prefix)+encoded)
+
+			dbuf, err := enc.AppendDecode(prefix[:len(prefix):len(prefix)], []byte(encoded))
+			testEqual(t, "AppendDecode(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "AppendDecode(%q) = %q, want %q", encoded, string(dbuf), string(prefix)+p.decoded)
+		}
+	}
+
+	got, err := AppendDecode(nil, []byte("3146755=0"))
+	if corruptOffset(err) != 7 || string(got) != "" {
+		t.Errorf("AppendDecode(corrupt) = %q, %v; want %q, offset %v", got, err, "", 7)
+	}
+	got, err = AppendDecode(nil, []byte("31467557314!"))
+	if corruptOffset(err) != 11 || string(got) != "foo" {
+		t.Errorf("AppendDecode(corrupt) = %q, %v; want %q, offset %v", got, err, "foo", 11)
+	}
+	testEqual(t, "AppendEncode(%q) = %q, want %q", "foo", string(AppendEncode(nil, []byte("foo"))), "31467557")
+}
+
+func TestDecodeConsumed(t *testing.T) {
+	for _, tc := range []struct {
+		enc     *Encoding
+		input   string
+		decoded string
+		nSrc    int
+		err     error
+	}{
+		{StdEncoding, "", "", 0, nil},
+		{StdEncoding, "31467557", "foo", 8, nil},
+		{StdEncoding, "314=====", "f", 8, nil},
+		{StdEncoding, "314=====&next=1", "f", 8, nil},
+		{StdEncoding, "31467557314674==31467557", "foofo", 16, nil},
+		{StdEncoding, "31467557314674==\x00", "foofo", 16, nil},
+		{StdEncoding, "3146755731", "foo", 8, CorruptInputError{offset: 8, b: 0, expected: ExpectPadding, err: ErrInvalidLength}},
+		{StdEncoding, "31467557314!6757", "foo", 8, CorruptInputError{offset: 11, b: '!', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+		{StdEncoding, "31======", "", 0, CorruptInputError{offset: 2, b: '=', expected: ExpectSymbol, err: ErrInvalidPadding}},
+		{RawEncoding, "31467557314", "foof", 11, nil},
+		{RawEncoding, "314=====", "", 0, CorruptInputError{offset: 3, b: '=', expected: ExpectSymbol, err: ErrInvalidCharacter}},
+		{StdEncoding.WithLengthEscape(), "31467557=1304&next=1", "foob", 13, nil},
+		{StdEncoding.WithLengthEscape(), "=2314674=1314", "fo", 8, nil},
+		{StdEncoding.WithLengthEscape(), "31467557", "foo", 8, nil},
+		{StdEncoding.WithLengthEscape(), "31467557=231", "foo", 8, CorruptInputError{offset: 8, b: 0, expected: ExpectSymbol, err: ErrInvalidLength}},
+		{StdEncoding.WithLengthEscape(), "314=====", "", 0, CorruptInputError{offset: 3, b: '=', expected: ExpectSymbol, err: ErrInvalidPadding}},
+	} {
+		dbuf := make([]byte, tc.enc.DecodedLen(len(tc.input)))
+		nDst, nSrc, err := tc.enc.DecodeConsumed(dbuf, []byte(tc.input))
+		testEqual(t, "DecodeConsumed(%q) = error %v, want %v", tc.input, err, tc.err)
+		testEqual(t, "DecodeConsumed(%q) = %q, want %q", tc.input, string(dbuf[:nDst]), tc.decoded)
+		testEqual(t, "DecodeConsumed(%q) = nSrc %d, want %d", tc.input, nSrc, tc.nSrc)
+	}
+}
+
+func TestNoPadLen(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 100)
+	for n := 0; n <= len(data); n++ {
+		encLen := EncodedLenNoPad(n)
+		enc := RawEncoding.EncodeToString(data[:n])
+		if len(enc) != encLen {
+			t.Fatalf("EncodedLenNoPad(%d) = %d but encoded to %q (%d)", n, encLen, enc, len(enc))
+		}
+		if decLen := DecodedLenNoPad(encLen); decLen != n {
+			t.Fatalf("DecodedLenNoPad(%d) = %d; want %d", encLen, decLen, n)
+		}
+	}
+}
+
+func TestLen64(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for n := 0; n <= 100; n++ {
+			testEqual(t, "EncodedLen64(%d) = %d, want %d", n, enc.EncodedLen64(int64(n)), int64(enc.EncodedLen(n)))
+			testEqual(t, "DecodedLen64(%d) = %d, want %d", n, enc.DecodedLen64(int64(n)), int64(enc.DecodedLen(n)))
+			got, err := enc.CheckedEncodedLen(int64(n))
+			testEqual(t, "CheckedEncodedLen(%d) = %d, want %d", n, got, enc.EncodedLen(n))
+			testEqual(t, "CheckedEncodedLen(%d) = error %v, want %v", n, err, error(nil))
+		}
+	}
+
+	// 6 GiB does not fit in a 32-bit int once encoded.
+	const big = 6 << 30
+	testEqual(t, "EncodedLen64(%d) = %d, want %d", int64(big), EncodedLen64(big), int64(big/3*8))
+	testEqual(t, "DecodedLen64(%d) = %d, want %d", int64(big/3*8), DecodedLen64(big/3*8), int64(big))
+	testEqual(t, "RawEncoding.EncodedLen64(%d) = %d, want %d", int64(big+1), RawEncoding.EncodedLen64(big+1), int64(big/3*8+3))
+
+	// The largest input whose encoding fits in an int.
+	maxIn := int64(math.MaxInt / 8 * 3)
+	if got, err := CheckedEncodedLen(maxIn); err != nil || got != math.MaxInt/8*8 {
+		t.Errorf("CheckedEncodedLen(%d) = %d, %v; want %d, nil", maxIn, got, err, math.MaxInt/8*8)
+	}
+	for _, n := range []int64{-1, maxIn + 1, math.MaxInt64} {
+		_, err := CheckedEncodedLen(n)
+		testEqual(t, "CheckedEncodedLen(%d) = error %v, want %v", n, err, ErrLengthOverflow)
+	}
+}
+
+func TestMustDecodeString(t *testing.T) {
+	testEqual(t, "MustDecodeString(%q) = %q, want %q", "31467557", string(MustDecodeString("31467557")), "foo")
+	testEqual(t, "MustDecodeString(%q) = %q, want %q", "314", string(RawEncoding.MustDecodeString("314")), "f")
+
+	defer func() {
+		r := recover()
+		want := `base8: DecodeString("314"): illegal base8 data at input byte 0`
+		if r != want {
+			t.Errorf("MustDecodeString(%q) panicked with %v, want %q", "314", r, want)
+		}
+	}()
+	MustDecodeString("314")
+}
+
+func TestValidate(t *testing.T) {
+	inputs := []string{
+		"",
+		"!!!!",
+		"x===",
+		"31467557=",
+		"314=====",
+		"31467557314674==",
+		"314674==31467557",
+		"3146755731467557314=====",
+		"31467557314675573146755!",
+		"314!6757314675573146755=",
+		"314675573146755731====",
+		bigtest.encoded,
+	}
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for _, input := range inputs {
+			wantOffset, wantErr := len(input), error(nil)
+			if _, err := enc.DecodeString(input); err != nil {
+				wantOffset, wantErr = int(err.(CorruptInputError).Offset()), err
+			}
+			offset, err := enc.Validate([]byte(input))
+			testEqual(t, "Validate(%q) = error %v, want %v", input, err, wantErr)
+			testEqual(t, "Validate(%q) = offset %d, want %d", input, offset, wantOffset)
+			testEqual(t, "IsValid(%q) = %v, want %v", input, enc.IsValid(input), wantErr == nil)
+		}
+	}
+
+	src := []byte(bigtest.encoded)
+	allocs := testing.AllocsPerRun(100, func() {
+		Validate(src)
+		IsValid(bigtest.encoded)
+	})
+	if allocs != 0 {
+		t.Errorf("Validate and IsValid allocated %v times, want 0", allocs)
+	}
+}
+
+func TestCorruptInputErrorDetails(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		offset   int64
+		b        byte
+		expected Expectation
+		class    error
+	}{
+		{"!!!!", 0, '!', ExpectSymbol, ErrInvalidCharacter},
+		{"3146755731467!57", 13, '!', ExpectSymbol, ErrInvalidCharacter},
+		{"314675573146755:", 15, ':', ExpectSymbol, ErrInvalidCharacter},
+		{"1=======", 1, '=', ExpectSymbol, ErrInvalidPadding},
+		{"1111111=", 7, '=', ExpectSymbol, ErrInvalidPadding},
+		{"31467557314=====31467557", 11, '=', ExpectSymbol, ErrInvalidPadding},
+		{"11=1====", 2, '1', ExpectPadding, ErrInvalidPadding},
+		{"11==", 4, 0, ExpectPadding, ErrInvalidLength},
+		{"222222", 0, 0, ExpectPadding, ErrInvalidLength},
+	} {
+		_, err := DecodeString(tc.input)
+		cie, ok := err.(CorruptInputError)
+		if !ok {
+			t.Errorf("DecodeString(%q) = %v, want CorruptInputError", tc.input, err)
+			continue
+		}
+		testEqual(t, "DecodeString(%q) Offset() = %v, want %v", tc.input, cie.Offset(), tc.offset)
+		testEqual(t, "DecodeString(%q) Byte() = %q, want %q", tc.input, cie.Byte(), tc.b)
+		testEqual(t, "DecodeString(%q) Expected() = %v, want %v", tc.input, cie.Expected(), tc.expected)
+		for _, class := range []error{ErrInvalidCharacter, ErrInvalidPadding, ErrInvalidLength} {
+			testEqual(t, "errors.Is(DecodeString(%q), %v) = %v, want %v", tc.input, class, errors.Is(err, class), class == tc.class)
+		}
+		testEqual(t, "Recoverable(DecodeString(%q)) = %v, want %v", tc.input, Recoverable(err), tc.class != ErrInvalidLength)
+	}
+	testEqual(t, "ExpectPadding.String() = %q, want %q", ExpectPadding.String(), "padding")
+
+	// Wrapped errors keep their class.
+	_, err := DecodeConcat("31467557", "3146755!")
+	testEqual(t, "errors.Is(DecodeConcat(...), %v) = %v, want %v", ErrInvalidCharacter, errors.Is(err, ErrInvalidCharacter), true)
+	testEqual(t, "Recoverable(DecodeConcat(...)) = %v, want %v", Recoverable(err), true)
+	_, err = DecodeConcat("31467557", "3146")
+	testEqual(t, "Recoverable(DecodeConcat(...)) = %v, want %v", Recoverable(err), false)
+	testEqual(t, "Recoverable(%v) = %v, want %v", io.ErrUnexpectedEOF, Recoverable(io.ErrUnexpectedEOF), false)
+	testEqual(t, "Recoverable(%v) = %v, want %v", nil, Recoverable(nil), false)
+}
+
+func TestWithStrictEnd(t *testing.T) {
+	strict := StdEncoding.WithStrictEnd()
+	for _, tc := range []struct {
+		input  string
+		offset int64
+		b      byte
+	}{
+		{"314=====", -1, 0},
+		{"314674==", -1, 0},
+		{"31467557", -1, 0},
+		{"314=====x", 8, 'x'},
+		{"314674==31", 8, '3'},
+		{"31467557314=====\n", 16, '\n'},
+	} {
+		_, err := strict.DecodeString(tc.input)
+		testEqual(t, "WithStrictEnd().DecodeString(%q) offset = %d, want %d", tc.input, corruptOffset(err), tc.offset)
+		_, err = io.ReadAll(strict.NewDecoderSize(strings.NewReader(tc.input), 8))
+		testEqual(t, "WithStrictEnd().NewDecoder(%q) offset = %d, want %d", tc.input, corruptOffset(err), tc.offset)
+		if tc.offset < 0 {
+			continue
+		}
+		if !errors.Is(err, ErrInvalidPadding) || err.(CorruptInputError).Byte() != tc.b || err.(CorruptInputError).Expected() != ExpectEnd {
+			t.Errorf("WithStrictEnd().NewDecoder(%q) = %v, want trailing %q", tc.input, err, tc.b)
+		}
+
+		// Without the option, the trailing data is ignored.
+		if _, err := StdEncoding.DecodeString(tc.input); err != nil {
+			t.Errorf("DecodeString(%q) = %v, want nil", tc.input, err)
+		}
+	}
+	testEqual(t, "ExpectEnd.String() = %q, want %q", ExpectEnd.String(), "end")
+
+	_, err := strict.WithIgnoredChars(" ").DecodeString("314 ===== x")
+	testEqual(t, "WithStrictEnd().WithIgnoredChars(...) offset = %d, want %d", corruptOffset(err), int64(10))
+}
+
+func TestDecodeStringInto(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for _, p := range append(pairs, bigtest) {
+			encoded := enc.EncodeToString([]byte(p.decoded))
+			dbuf := make([]byte, enc.DecodedLen(len(encoded)))
+			n, err := enc.DecodeStringInto(dbuf, encoded)
+			testEqual(t, "DecodeStringInto(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "DecodeStringInto(%q) = %q, want %q", encoded, string(dbuf[:n]), p.decoded)
+		}
+	}
+
+	dbuf := make([]byte, 16)
+	n, err := DecodeStringInto(dbuf, "31467557314!6757")
+	if string(dbuf[:n]) != "foo" || corruptOffset(err) != 11 {
+		t.Errorf("DecodeStringInto(corrupt) = %q, %v; want %q, offset %d", dbuf[:n], err, "foo", 11)
+	}
+
+	dbuf = make([]byte, DecodedLen(len(bigtest.encoded)))
+	allocs := testing.AllocsPerRun(100, func() {
+		DecodeStringInto(dbuf, bigtest.encoded)
+	})
+	if allocs != 0 {
+		t.Errorf("DecodeStringInto allocated %v times, want 0", allocs)
+	}
+}
+
+func TestEncodeToStringAllocs(t *testing.T) {
+	data := make([]byte, 8192)
+	allocs := testing.AllocsPerRun(100, func() {
+		EncodeToString(data)
+	})
+	if allocs != 1 {
+		t.Errorf("EncodeToString allocated %v times, want 1", allocs)
+	}
+}
+
+func TestStreamAllocs(t *testing.T) {
+	data := make([]byte, 4096)
+	encoded := []byte(EncodeToString(data))
+	var out bytes.Buffer
+	out.Grow(len(encoded))
+	var r bytes.Reader
+
+	// Only the encoder or decoder itself is allocated; its buffers come from
+	// a pool once it is finished.
+	allocs := testing.AllocsPerRun(100, func() {
+		out.Reset()
+		w := NewEncoder(&out)
+		w.Write(data)
+		w.Close()
+	})
+	if allocs > 1 {
+		t.Errorf("NewEncoder, Write and Close allocated %v times, want at most 1", allocs)
+	}
+	allocs = testing.AllocsPerRun(100, func() {
+		r.Reset(encoded)
+		io.Copy(io.Discard, NewDecoder(&r))
+	})
+	if allocs > 1 {
+		t.Errorf("NewDecoder and io.Copy allocated %v times, want at most 1", allocs)
+	}
+}
+
+func TestStreamReuseAfterRelease(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		var bb bytes.Buffer
+		w := NewEncoder(&bb)
+		for i := 0; i < 2; i++ {
+			bb.Reset()
+			w.(*encoder).Reset(&bb)
+			io.WriteString(w, p.decoded)
+			w.Close()
+			testEqual(t, "Encode(%q) after Reset = %q, want %q", p.decoded, bb.String(), p.encoded)
+		}
+
+		d := NewDecoder(strings.NewReader(p.encoded))
+		for _, read := range []func() ([]byte, error){
+			func() ([]byte, error) { return io.ReadAll(d) },
+			func() ([]byte, error) {
+				var out bytes.Buffer
+				_, err := d.(io.WriterTo).WriteTo(&out)
+				return out.Bytes(), err
+			},
+			func() ([]byte, error) {
+				var out []byte
+				for {
+					b, err := d.(io.ByteReader).ReadByte()
+					if err == io.EOF {
+						return out, nil
+					} else if err != nil {
+						return out, err
+					}
+					out = append(out, b)
+				}
+			},
+		} {
+			d.(*decoder).Reset(strings.NewReader(p.encoded))
+			dbuf, err := read()
+			testEqual(t, "Decode(%q) after Reset = error %v, want %v", p.encoded, err, error(nil))
+			testEqual(t, "Decode(%q) after Reset = %q, want %q", p.encoded, string(dbuf), p.decoded)
+		}
+	}
+}
+
+func TestDecodeStringAllocs(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		encoded := enc.EncodeToString(make([]byte, 8192))
+		dbuf, err := enc.DecodeString(encoded)
+		if err != nil || len(dbuf) != 8192 || cap(dbuf) > 8192+2 {
+			t.Errorf("DecodeString(8192 bytes) = len %d, cap %d, %v; want len 8192, cap at most 8194, nil", len(dbuf), cap(dbuf), err)
+		}
+		allocs := testing.AllocsPerRun(100, func() {
+			enc.DecodeString(encoded)
+		})
+		if allocs != 1 {
+			t.Errorf("DecodeString allocated %v times, want 1", allocs)
+		}
+	}
+}
+
+func TestAny(t *testing.T) {
+	type key string
+	for _, p := range append(pairs, bigtest) {
+		testEqual(t, "EncodeToStringAny(%q) = %q, want %q", p.decoded, EncodeToStringAny(p.decoded), p.encoded)
+		testEqual(t, "EncodeToStringAny(key(%q)) = %q, want %q", p.decoded, EncodeToStringAny(key(p.decoded)), p.encoded)
+		testEqual(t, "EncodeToStringAny([]byte(%q)) = %q, want %q", p.decoded, EncodeToStringAny([]byte(p.decoded)), p.encoded)
+
+		dbuf := make([]byte, DecodedLen(len(p.encoded)))
+		n, err := DecodeAny(dbuf, p.encoded)
+		testEqual(t, "DecodeAny(%q) = error %v, want %v", p.encoded, err, error(nil))
+		testEqual(t, "DecodeAny(%q) = %q, want %q", p.encoded, string(dbuf[:n]), p.decoded)
+		n, err = DecodeAny(dbuf, []byte(p.encoded))
+		testEqual(t, "DecodeAny([]byte(%q)) = error %v, want %v", p.encoded, err, error(nil))
+		testEqual(t, "DecodeAny([]byte(%q)) = %q, want %q", p.encoded, string(dbuf[:n]), p.decoded)
+	}
+
+	_, err := DecodeAny(make([]byte, 8), "31=~~~~~")
+	testEqual(t, "DecodeAny(%q) = %v, want %v", "31=~~~~~", err, error(invalidPadding(2, '~')))
+
+	encoded := EncodeToString(make([]byte, 8192))
+	dbuf := make([]byte, 8192)
+	allocs := testing.AllocsPerRun(100, func() {
+		DecodeAny(dbuf, encoded)
+	})
+	if allocs != 0 {
+		t.Errorf("DecodeAny allocated %v times, want 0", allocs)
+	}
+	data := string(dbuf)
+	allocs = testing.AllocsPerRun(100, func() {
+		EncodeToStringAny(data)
+	})
+	if allocs != 1 {
+		t.Errorf("EncodeToStringAny allocated %v times, want 1", allocs)
+	}
+}
+
+func TestLetterEncoding(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		want := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '7' {
+				return r - '0' + 'a'
+			}
+			return r
+		}, p.encoded)
+		got := LetterEncoding.EncodeToString([]byte(p.decoded))
+		testEqual(t, "LetterEncoding.Encode(%q) = %q, want %q", p.decoded, got, want)
+
+		dbuf, err := LetterEncoding.DecodeString(want)
+		testEqual(t, "LetterEncoding.DecodeString(%q) = error %v, want %v", want, err, error(nil))
+		testEqual(t, "LetterEncoding.DecodeString(%q) = %q, want %q", want, string(dbuf), p.decoded)
+
+		bb := &bytes.Buffer{}
+		w := LetterEncoding.NewEncoder(bb)
+		w.Write([]byte(p.decoded))
+		w.Close()
+		testEqual(t, "LetterEncoding.NewEncoder(%q) = %q, want %q", p.decoded, bb.String(), want)
+
+		dbuf, err = ioutil.ReadAll(LetterEncoding.NewDecoder(strings.NewReader(want)))
+		testEqual(t, "LetterEncoding.NewDecoder(%q) = error %v, want %v", want, err, error(nil))
+		testEqual(t, "LetterEncoding.NewDecoder(%q) = %q, want %q", want, string(dbuf), p.decoded)
+	}
+
+	if _, err := LetterEncoding.DecodeString("31467557"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("LetterEncoding.DecodeString(digits) = %v, want ErrInvalidCharacter", err)
+	}
+}
+
+// referenceEncodeLSB encodes src least-significant bit first, one bit at a
+// time: the bits of each byte are taken from bit 0 upwards, and each digit is
+// assembled from bit 0 upwards.
+func referenceEncodeLSB(src []byte) string {
+	var out []byte
+	for len(src) > 0 {
+		n := len(src)
+		if n > 3 {
+			n = 3
+		}
+		var digits [8]byte
+		for i := 0; i < 24; i++ {
+			if i/8 < n && src[i/8]>>(i%8)&1 != 0 {
+				digits[i/3] |= 1 << (i % 3)
+			}
+		}
+		for i, d := range digits {
+			switch {
+			case i < EncodedLenNoPad(n):
+				out = append(out, '0'+d)
+			default:
+				out = append(out, '=')
+			}
+		}
+		src = src[n:]
+	}
+	return string(out)
+}
+
+func TestSWAR(t *testing.T) {
+	src := make([]byte, 100)
+	rand.New(rand.NewSource(1)).Read(src)
+	for _, alphabet := range []string{"01234567", "@ABCDEFG", "pqrstuvw"} {
+		for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+			fast := NewEncoding(alphabet).WithBitOrder(order)
+			if !fast.swar {
+				t.Fatalf("NewEncoding(%q) does not use the SWAR fast paths", alphabet)
+			}
+			slow := *fast
+			slow.swar = false
+			for n := 0; n <= len(src); n++ {
+				want := slow.EncodeToString(src[0:n])
+				got := fast.EncodeToString(src[0:n])
+				testEqual(t, "SWAR EncodeToString(%q) = %q, want %q", src[0:n], got, want)
+
+				dbuf, err := fast.DecodeString(want)
+				testEqual(t, "SWAR DecodeString(%q) = error %v, want %v", want, err, error(nil))
+				testEqual(t, "SWAR DecodeString(%q) = %q, want %q", want, string(dbuf), string(src[0:n]))
+			}
+		}
+	}
+}
+
+func TestPairs(t *testing.T) {
+	src := make([]byte, 100)
+	rand.New(rand.NewSource(1558)).Read(src)
+	toLetter := strings.NewReplacer("0", "a", "1", "b", "2", "c", "3", "d", "4", "e", "5", "f", "6", "g", "7", "h")
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		std := StdEncoding.WithBitOrder(order)
+		letter := LetterEncoding.WithBitOrder(order)
+		if letter.swar {
+			t.Fatalf("LetterEncoding uses the SWAR fast paths")
+		}
+		for n := 0; n <= len(src); n++ {
+			want := toLetter.Replace(std.EncodeToString(src[0:n]))
+			got := letter.EncodeToString(src[0:n])
+			testEqual(t, "EncodeToString(%q) = %q, want %q", src[0:n], got, want)
+		}
+	}
+}
+
+func TestScalarDecode(t *testing.T) {
+	src := make([]byte, 60)
+	rand.New(rand.NewSource(1560)).Read(src)
+	toLetter := strings.NewReplacer("0", "a", "1", "b", "2", "c", "3", "d", "4", "e", "5", "f", "6", "g", "7", "h")
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		std := StdEncoding.WithBitOrder(order)
+		letter := LetterEncoding.WithBitOrder(order)
+		for n := 0; n <= len(src); n++ {
+			encoded := letter.EncodeToString(src[0:n])
+			dbuf, err := letter.DecodeString(encoded)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", encoded, string(dbuf), string(src[0:n]))
+		}
+
+		encoded := std.EncodeToString(src)
+		for i := range encoded {
+			for _, c := range "z=" {
+				corrupt := encoded[:i] + string(c) + encoded[i+1:]
+				// The offending byte may be a symbol, which differs between
+				// the alphabets.
+				_, want := std.DecodeString(corrupt)
+				_, err := letter.DecodeString(toLetter.Replace(corrupt))
+				w, e := want.(CorruptInputError), err.(CorruptInputError)
+				w.b, e.b = 0, 0
+				testEqual(t, "DecodeString(%q) = error %#v, want %#v", corrupt, e, w)
+			}
+		}
+	}
+}
+
+func TestBitReversed(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, LetterEncoding, StdEncoding.WithBitOrder(LSBFirst)} {
+		rev := enc.BitReversed()
+		testEqual(t, "BitReversed().lsb = %v, want %v", rev.lsb, !enc.lsb)
+		testEqual(t, "BitReversed().BitReversed() = %v, want %v", *rev.BitReversed() == *enc, true)
+
+		// Encoding with one and decoding with the other does not round-trip.
+		src := []byte("foobar")
+		dbuf, err := rev.DecodeString(enc.EncodeToString(src))
+		testEqual(t, "DecodeString(EncodeToString(%q)) = error %v, want %v", src, err, error(nil))
+		if string(dbuf) == string(src) {
+			t.Errorf("BitReversed().DecodeString(EncodeToString(%q)) = %q, want different bytes", src, dbuf)
+		}
+	}
+}
+
+func TestLSBFirst(t *testing.T) {
+	enc := StdEncoding.WithBitOrder(LSBFirst)
+	testEqual(t, "LSBFirst.Encode(%q) = %q, want %q", "f", enc.EncodeToString([]byte("f")), "641=====")
+
+	rng := rand.New(rand.NewSource(1516))
+	inputs := []string{bigtest.decoded, "\x00\x01\x02\xfd\xfe\xff"}
+	for _, p := range pairs {
+		inputs = append(inputs, p.decoded)
+	}
+	for i := 0; i < 100; i++ {
+		b := make([]byte, rng.Intn(20))
+		rng.Read(b)
+		inputs = append(inputs, string(b))
+	}
+	for _, input := range inputs {
+		want := referenceEncodeLSB([]byte(input))
+		got := enc.EncodeToString([]byte(input))
+		testEqual(t, "LSBFirst.Encode(%q) = %q, want %q", input, got, want)
+
+		dbuf, err := enc.DecodeString(want)
+		testEqual(t, "LSBFirst.DecodeString(%q) = error %v, want %v", want, err, error(nil))
+		testEqual(t, "LSBFirst.DecodeString(%q) = %q, want %q", want, string(dbuf), input)
+
+		var seq []byte
+		for b, err := range enc.DecodeSeq(want) {
+			if err != nil {
+				t.Fatalf("LSBFirst.DecodeSeq(%q): %v", want, err)
+			}
+			seq = append(seq, b)
+		}
+		testEqual(t, "LSBFirst.DecodeSeq(%q) = %q, want %q", want, string(seq), input)
+
+		dbuf, err = ioutil.ReadAll(enc.NewDecoder(strings.NewReader(want)))
+		testEqual(t, "LSBFirst.NewDecoder(%q) = error %v, want %v", want, err, error(nil))
+		testEqual(t, "LSBFirst.NewDecoder(%q) = %q, want %q", want, string(dbuf), input)
+
+		raw := enc.WithPadding(NoPadding)
+		dbuf, err = raw.DecodeString(raw.EncodeToString([]byte(input)))
+		testEqual(t, "LSBFirst raw round trip of %q = error %v, want %v", input, err, error(nil))
+		testEqual(t, "LSBFirst raw round trip of %q = %q, want %q", input, string(dbuf), input)
+	}
+}
+
+func TestOrderPreservation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1517))
+	inputs := [][]byte{{}, {0}, {0, 0}, {0, 1}, {1}, {0xff}, {0xff, 0}, {0xff, 0xff, 0xff}}
+	for i := 0; i < 200; i++ {
+		// Use a small byte range so that shared prefixes are common.
+		b := make([]byte, rng.Intn(8))
+		for j := range b {
+			b[j] = byte(rng.Intn(3)) * 0x7f
+		}
+		inputs = append(inputs, b)
+	}
+
+	sign := func(x int) int {
+		switch {
+		case x < 0:
+			return -1
+		case x > 0:
+			return +1
+		}
+		return 0
+	}
+	lsb := StdEncoding.WithBitOrder(LSBFirst)
+	esc := StdEncoding.WithLengthEscape()
+	for _, a := range inputs {
+		for _, b := range inputs {
+			want := bytes.Compare(a, b)
+			rawA, rawB := RawEncoding.EncodeToString(a), RawEncoding.EncodeToString(b)
+			testEqual(t, "strings.Compare(Raw(%x), Raw(%x)) = %v, want %v", a, b, sign(strings.Compare(rawA, rawB)), want)
+			testEqual(t, "Compare(%x, %x) = %v, want %v", a, b, Compare(EncodeToString(a), EncodeToString(b)), want)
+			testEqual(t, "Compare(%x, raw %x) = %v, want %v", a, b, Compare(EncodeToString(a), rawB), want)
+			testEqual(t, "LetterEncoding.Compare(%x, %x) = %v, want %v", a, b,
+				LetterEncoding.Compare(LetterEncoding.EncodeToString(a), LetterEncoding.EncodeToString(b)), want)
+			testEqual(t, "LSBFirst.Compare(%x, %x) = %v, want %v", a, b, lsb.Compare(lsb.EncodeToString(a), lsb.EncodeToString(b)), want)
+			testEqual(t, "WithLengthEscape().Compare(%x, %x) = %v, want %v", a, b, esc.Compare(esc.EncodeToString(a), esc.EncodeToString(b)), want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package base8
+
+import "net"
+
+// EncodeToBuffers returns the base8 encoding of src split into chunks of at
+// most chunkSize bytes, suitable for scatter-gather writes. chunkSize is
+// rounded down to a multiple of 8 (but no less than 8) so that every chunk
+// except the last holds only complete quanta.
+func EncodeToBuffers(src []byte, chunkSize int) net.Buffers {
+	if chunkSize < 8 {
+		chunkSize = 8
+	}
+	nn := chunkSize / 8 * 3
+
+	bufs := make(net.Buffers, 0, (len(src)+nn-1)/nn)
+	for len(src) > 0 {
+		if nn > len(src) {
+			nn = len(src)
+		}
+		chunk := make([]byte, EncodedLen(nn))
+		Encode(chunk, src[0:nn])
+		bufs = append(bufs, chunk)
+		src = src[nn:]
+	}
+	return bufs
+}
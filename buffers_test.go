@@ -0,0 +1,26 @@
+package base8
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeToBuffers(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		for _, chunkSize := range []int{0, 8, 15, 16, 24, 1024} {
+			bufs := EncodeToBuffers([]byte(p.decoded), chunkSize)
+			for i, b := range bufs {
+				if len(b)%8 != 0 || len(b) > chunkSize && len(b) > 8 {
+					t.Errorf("EncodeToBuffers(%q, %d) chunk %d has length %d", p.decoded, chunkSize, i, len(b))
+				}
+				if i < len(bufs)-1 && bytes.IndexByte(b, PadChar) != -1 {
+					t.Errorf("EncodeToBuffers(%q, %d) chunk %d = %q contains padding", p.decoded, chunkSize, i, b)
+				}
+			}
+
+			var joined bytes.Buffer
+			bufs.WriteTo(&joined)
+			testEqual(t, "EncodeToBuffers(%q, %d) = %q, want %q", p.decoded, chunkSize, joined.String(), p.encoded)
+		}
+	}
+}
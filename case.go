@@ -0,0 +1,30 @@
+package base8
+
+// CaseInsensitive creates a new encoding identical to enc except that its
+// decoders accept either case of the letters of its alphabet, as with codes
+// typed by hand. By default, the uppercase form of a lowercase letter of the
+// alphabet is rejected with a CorruptInputError that wraps ErrUppercase, and
+// the lowercase form of an uppercase letter with one that wraps
+// ErrInvalidCharacter. It panics if the other case of a letter is another
+// symbol of the alphabet, its padding character or an ignored character. The
+// encoder is unaffected and always produces the alphabet's own case.
+func (enc Encoding) CaseInsensitive() *Encoding {
+	for i, c := range enc.encode {
+		var other byte
+		switch {
+		case 'a' <= c && c <= 'z':
+			other = c - 'a' + 'A'
+		case 'A' <= c && c <= 'Z':
+			other = c - 'A' + 'a'
+		default:
+			continue
+		}
+		switch enc.decodeMap[other] {
+		case invalidIndex, byte(i):
+			enc.decodeMap[other] = byte(i)
+		default:
+			panic("encoding alphabet is ambiguous without case")
+		}
+	}
+	return &enc
+}
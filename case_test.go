@@ -0,0 +1,80 @@
+package base8
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUppercaseRejected(t *testing.T) {
+	encoded := LetterEncoding.EncodeToString([]byte("foobar"))
+	upper := strings.ToUpper(encoded)
+
+	_, err := LetterEncoding.DecodeString(upper)
+	want := CorruptInputError{offset: 0, b: upper[0], expected: ExpectSymbol, err: ErrUppercase}
+	testEqual(t, "DecodeString(%q) = %#v, want %#v", upper, err, error(want))
+	testEqual(t, "errors.Is(DecodeString(%q), ErrUppercase) = %v, want %v", upper, errors.Is(err, ErrUppercase), true)
+	testEqual(t, "Recoverable(DecodeString(%q)) = %v, want %v", upper, Recoverable(err), true)
+
+	mixed := encoded[:5] + strings.ToUpper(encoded[5:6]) + encoded[6:]
+	_, err = LetterEncoding.DecodeString(mixed)
+	testEqual(t, "DecodeString(%q) offset = %v, want %v", mixed, corruptOffset(err), int64(5))
+	testEqual(t, "errors.Is(DecodeString(%q), ErrUppercase) = %v, want %v", mixed, errors.Is(err, ErrUppercase), true)
+
+	// Other letters are merely invalid.
+	_, err = LetterEncoding.DecodeString("Zbcdefgh")
+	testEqual(t, "errors.Is(DecodeString(%q), ErrInvalidCharacter) = %v, want %v", "Zbcdefgh", errors.Is(err, ErrInvalidCharacter), true)
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	for _, enc := range []*Encoding{LetterEncoding, NewEncoding("ABCDEFGH"), NewEncoding("aBcD0123")} {
+		fold := enc.CaseInsensitive()
+		for _, p := range append(pairs, bigtest) {
+			encoded := fold.EncodeToString([]byte(p.decoded))
+			testEqual(t, "EncodeToString(%q) = %q, want %q", p.decoded, encoded, enc.EncodeToString([]byte(p.decoded)))
+			for _, in := range []string{encoded, strings.ToUpper(encoded), strings.ToLower(encoded)} {
+				dbuf, err := fold.DecodeString(in)
+				testEqual(t, "DecodeString(%q) = error %v, want %v", in, err, error(nil))
+				testEqual(t, "DecodeString(%q) = %q, want %q", in, string(dbuf), p.decoded)
+			}
+		}
+	}
+
+	// Digits have no case.
+	testEqual(t, "CaseInsensitive().decodeMap = %v, want %v", StdEncoding.CaseInsensitive().decodeMap, StdEncoding.decodeMap)
+}
+
+func TestCaseInsensitivePanics(t *testing.T) {
+	for _, mk := range []func() *Encoding{
+		func() *Encoding { return NewEncoding("aAbcdefg").CaseInsensitive() },
+		func() *Encoding { return NewEncoding("abcdefgh").WithPadding('H').CaseInsensitive() },
+		func() *Encoding { return NewEncoding("abcdefgh").WithIgnoredChars("C").CaseInsensitive() },
+		func() *Encoding { return NewEncoding("abcdefgh").CaseInsensitive().WithPadding('H') },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			mk()
+		}()
+	}
+}
+
+func TestSpeakSafeEncoding(t *testing.T) {
+	enc := NewSpeakSafeEncoding()
+	testEqual(t, "NewSpeakSafeEncoding() alphabet = %q, want %q", string(enc.encode[0:]), "ailoruwx")
+	testEqual(t, "SelfTest() = %v, want %v", enc.SelfTest(), error(nil))
+	for _, p := range append(pairs, bigtest) {
+		encoded := enc.EncodeToString([]byte(p.decoded))
+		if strings.Trim(encoded, "ailoruwx=") != "" {
+			t.Errorf("EncodeToString(%q) = %q, want only speak-safe letters", p.decoded, encoded)
+		}
+		for _, in := range []string{encoded, strings.ToUpper(encoded)} {
+			dbuf, err := enc.DecodeString(in)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", in, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", in, string(dbuf), p.decoded)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package base8
+
+import "io"
+
+type decoderCloser struct {
+	*decoder
+	c io.Closer
+}
+
+// Close closes the underlying reader. If that succeeds but the decoder
+// reached the end of its input in the middle of a quantum, Close returns
+// io.ErrUnexpectedEOF.
+func (d *decoderCloser) Close() error {
+	if err := d.c.Close(); err != nil {
+		return err
+	}
+	if d.err == io.ErrUnexpectedEOF {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// NewDecoderCloser is like NewDecoder, but the returned decoder's Close
+// method closes r, so that a decoder that is the last consumer of a file or
+// network stream can release it. Close also reports io.ErrUnexpectedEOF if
+// the input ended in the middle of a quantum, so that truncation is caught
+// even by callers that only check the error from Close. Input the caller did
+// not read is not checked.
+func (enc *Encoding) NewDecoderCloser(r io.ReadCloser) io.ReadCloser {
+	return &decoderCloser{decoder: enc.NewDecoder(r).(*decoder), c: r}
+}
+
+// NewDecoderCloser is like NewDecoder for the standard encoding, but the
+// returned decoder's Close method closes r.
+func NewDecoderCloser(r io.ReadCloser) io.ReadCloser {
+	return StdEncoding.NewDecoderCloser(r)
+}
+
+type owningEncoder struct {
+	*encoder
+	c io.Closer
+}
+
+// Close flushes any pending output from the encoder and then closes the
+// underlying writer, even if flushing failed. It returns the first error.
+func (e *owningEncoder) Close() error {
+	err := e.encoder.Close()
+	if cerr := e.c.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// NewEncoderOwning is like NewEncoder, but the returned encoder's Close
+// method closes w after flushing the final partial block, so that callers
+// need not close the encoder and then w in that order.
+func (enc *Encoding) NewEncoderOwning(w io.WriteCloser) io.WriteCloser {
+	return &owningEncoder{encoder: enc.NewEncoder(w).(*encoder), c: w}
+}
+
+// NewEncoderOwning is like NewEncoder for the standard encoding, but the
+// returned encoder's Close method also closes w.
+func NewEncoderOwning(w io.WriteCloser) io.WriteCloser {
+	return StdEncoding.NewEncoderOwning(w)
+}
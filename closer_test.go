@@ -0,0 +1,85 @@
+package base8
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed int
+	err    error
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return c.err
+}
+
+func TestDecoderCloser(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"3146755730460562", nil},
+		{"314=====", nil},
+		{"31467557304", io.ErrUnexpectedEOF},
+	} {
+		c := &closeRecorder{Reader: strings.NewReader(tc.input)}
+		d := NewDecoderCloser(c)
+		io.ReadAll(d)
+		testEqual(t, "NewDecoderCloser(%q).Close() = %v, want %v", tc.input, d.Close(), tc.err)
+		testEqual(t, "NewDecoderCloser(%q) closed underlying reader %d times, want %d", tc.input, c.closed, 1)
+	}
+
+	want := errors.New("close failed")
+	c := &closeRecorder{Reader: strings.NewReader("31467557304"), err: want}
+	d := NewDecoderCloser(c)
+	io.ReadAll(d)
+	testEqual(t, "NewDecoderCloser.Close() = %v, want %v", d.Close(), want)
+
+	// The decoder's other methods are still available.
+	if _, ok := NewDecoderCloser(c).(io.WriterTo); !ok {
+		t.Errorf("NewDecoderCloser does not implement io.WriterTo")
+	}
+}
+
+type closeOrderWriter struct {
+	strings.Builder
+	closedAfter string // contents when Close was called
+	closed      int
+	err         error
+}
+
+func (w *closeOrderWriter) Close() error {
+	w.closed++
+	w.closedAfter = w.String()
+	return w.err
+}
+
+func TestEncoderOwning(t *testing.T) {
+	w := &closeOrderWriter{}
+	e := NewEncoderOwning(w)
+	e.Write([]byte("foob"))
+	testEqual(t, "NewEncoderOwning.Close() = %v, want %v", e.Close(), error(nil))
+	testEqual(t, "NewEncoderOwning closed underlying writer %d times, want %d", w.closed, 1)
+	testEqual(t, "NewEncoderOwning closed underlying writer after %q, want %q", w.closedAfter, "31467557304=====")
+
+	want := errors.New("close failed")
+	w = &closeOrderWriter{err: want}
+	e = NewEncoderOwning(w)
+	e.Write([]byte("f"))
+	testEqual(t, "NewEncoderOwning.Close() = %v, want %v", e.Close(), want)
+
+	// The underlying writer is closed even if the final write fails.
+	c := &closeRecorder{}
+	e = NewEncoderOwning(struct {
+		io.Writer
+		io.Closer
+	}{errWriter{want}, c})
+	e.Write([]byte("f"))
+	testEqual(t, "NewEncoderOwning.Close() = %v, want %v", e.Close(), want)
+	testEqual(t, "NewEncoderOwning closed underlying writer %d times, want %d", c.closed, 1)
+}
@@ -0,0 +1,132 @@
+package base8
+
+import "io"
+
+type translatingDecoder struct {
+	r     io.Reader
+	table *[256]byte
+}
+
+func (d *translatingDecoder) Read(p []byte) (n int, err error) {
+	n, err = d.r.Read(p)
+	for i, b := range p[0:n] {
+		p[i] = d.table[b]
+	}
+	return n, err
+}
+
+// NewTranslatingDecoder constructs a new base8 stream decoder that maps each
+// decoded byte b to table[b] before returning it.
+func NewTranslatingDecoder(r io.Reader, table *[256]byte) io.Reader {
+	return &translatingDecoder{r: NewDecoder(r), table: table}
+}
+
+// NewMultiEncoder returns a new base8 stream encoder that writes its encoded
+// output to each of ws, similar to io.MultiWriter. Data is encoded only once.
+// If any writer returns an error, encoding stops and the error is returned
+// from the current and all subsequent calls to Write and Close.
+func NewMultiEncoder(ws ...io.Writer) io.WriteCloser {
+	return NewEncoder(io.MultiWriter(ws...))
+}
+
+type teeEncoder struct {
+	*encoder
+	tee io.Writer
+}
+
+func (e *teeEncoder) Write(p []byte) (n int, err error) {
+	n, err = e.encoder.Write(p)
+	if n > 0 {
+		if _, terr := e.tee.Write(p[0:n]); err == nil {
+			err = terr
+		}
+	}
+	return n, err
+}
+
+func (e *teeEncoder) WriteString(s string) (n int, err error) {
+	n, err = e.encoder.WriteString(s)
+	if n > 0 {
+		if _, terr := io.WriteString(e.tee, s[0:n]); err == nil {
+			err = terr
+		}
+	}
+	return n, err
+}
+
+// ReadFrom encodes the data read from r until EOF, writing each block read to
+// tee as well.
+func (e *teeEncoder) ReadFrom(r io.Reader) (n int64, err error) {
+	return e.encoder.ReadFrom(io.TeeReader(r, e.tee))
+}
+
+// NewTeeEncoder returns a new base8 stream encoder that encodes the data
+// written to it using enc and writes the result to w, and also writes the
+// data itself, unencoded, to tee. Passing a hash.Hash as tee computes a digest
+// of the data in the same pass that encodes it. Only the data accepted by the
+// encoder is written to tee. As with NewEncoder, the caller must Close the
+// returned encoder to flush any partially written blocks, and the encoder has
+// the same methods as one returned by NewEncoder; its WriteString and
+// ReadFrom methods also write to tee.
+func (enc *Encoding) NewTeeEncoder(w, tee io.Writer) io.WriteCloser {
+	return &teeEncoder{encoder: enc.NewEncoder(w).(*encoder), tee: tee}
+}
+
+// NewTeeEncoder returns a new base8 stream encoder that uses the standard
+// encoding and also writes the unencoded data to tee.
+func NewTeeEncoder(w, tee io.Writer) io.WriteCloser {
+	return StdEncoding.NewTeeEncoder(w, tee)
+}
+
+// CountDecoded decodes the base8 stream read from r and returns the total
+// number of decoded bytes and the number of those bytes for which pred
+// returns true. The decoded data is not retained.
+func CountDecoded(r io.Reader, pred func(byte) bool) (total, matched int64, err error) {
+	var buf [1024 / 8 * 3]byte
+	d := NewDecoder(r)
+	for {
+		n, err := d.Read(buf[0:])
+		for _, b := range buf[0:n] {
+			if pred(b) {
+				matched++
+			}
+		}
+		total += int64(n)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return total, matched, err
+		}
+	}
+}
+
+type pipeEncoder struct {
+	io.WriteCloser
+	pw *io.PipeWriter
+}
+
+// Close flushes any pending output from the encoder and closes the pipe, so
+// that the decoder returns io.EOF once it has returned all the data.
+func (e *pipeEncoder) Close() error {
+	err := e.WriteCloser.Close()
+	e.pw.CloseWithError(err)
+	return err
+}
+
+// Pipe returns a connected stream encoder and decoder. Data written to w is
+// encoded using enc, passed through an in-memory io.Pipe, and decoded when
+// read from r, so the two ends are guaranteed to agree on the encoding. As
+// with io.Pipe, each Write to w blocks until the encoded data has been
+// consumed by reads from r. Closing w flushes any partial block and causes r
+// to return io.EOF after the remaining data.
+func (enc *Encoding) Pipe() (w io.WriteCloser, r io.Reader) {
+	pr, pw := io.Pipe()
+	return &pipeEncoder{WriteCloser: enc.NewEncoder(pw), pw: pw}, enc.NewDecoder(pr)
+}
+
+// Pipe returns a connected stream encoder and decoder that use the standard
+// encoding.
+func Pipe() (w io.WriteCloser, r io.Reader) {
+	return StdEncoding.Pipe()
+}
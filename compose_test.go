@@ -0,0 +1,170 @@
+package base8
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTranslatingDecoder(t *testing.T) {
+	var identity, invert [256]byte
+	for i := range identity {
+		identity[i] = byte(i)
+		invert[i] = ^byte(i)
+	}
+
+	decoded, err := ioutil.ReadAll(NewTranslatingDecoder(strings.NewReader(bigtest.encoded), &identity))
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(NewTranslatingDecoder(...)): %v", err)
+	}
+	testEqual(t, "Translating decode of %q = %q, want %q", bigtest.encoded, string(decoded), bigtest.decoded)
+
+	decoded, err = ioutil.ReadAll(NewTranslatingDecoder(strings.NewReader(bigtest.encoded), &invert))
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(NewTranslatingDecoder(...)): %v", err)
+	}
+	want := []byte(bigtest.decoded)
+	for i := range want {
+		want[i] = ^want[i]
+	}
+	testEqual(t, "Translating decode of %q = %q, want %q", bigtest.encoded, string(decoded), string(want))
+
+	_, err = ioutil.ReadAll(NewTranslatingDecoder(strings.NewReader("01234568"), &identity))
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("Corrupt input error expected.  Found %T (%v)", err, err)
+	}
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestMultiEncoder(t *testing.T) {
+	var a, b bytes.Buffer
+	w := NewMultiEncoder(&a, &b)
+	w.Write([]byte(bigtest.decoded))
+	if err := w.Close(); err != nil {
+		t.Fatalf("MultiEncoder.Close() = %v want nil", err)
+	}
+	testEqual(t, "MultiEncoder first output = %q, want %q", a.String(), bigtest.encoded)
+	testEqual(t, "MultiEncoder second output = %q, want %q", b.String(), bigtest.encoded)
+
+	want := errors.New("sink failed")
+	w = NewMultiEncoder(&a, errWriter{want})
+	w.Write([]byte("f"))
+	err := w.Close()
+	testEqual(t, "MultiEncoder.Close() = %v, want %v", err, want)
+	_, err = w.Write([]byte("foo"))
+	testEqual(t, "MultiEncoder.Write() = %v, want %v", err, want)
+}
+
+func TestTeeEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	h := sha256.New()
+	w := NewTeeEncoder(&buf, h)
+	input := []byte(bigtest.decoded)
+	for pos := 0; pos < len(input); pos += 5 {
+		w.Write(input[pos:min(pos+5, len(input))])
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("TeeEncoder.Close() = %v want nil", err)
+	}
+	testEqual(t, "TeeEncoder output = %q, want %q", buf.String(), bigtest.encoded)
+	sum := sha256.Sum256(input)
+	testEqual(t, "TeeEncoder digest = %x, want %x", string(h.Sum(nil)), string(sum[:]))
+
+	want := errors.New("tee failed")
+	w = NewTeeEncoder(&buf, errWriter{want})
+	_, err := w.Write([]byte("foo"))
+	testEqual(t, "TeeEncoder.Write() = %v, want %v", err, want)
+}
+
+func TestTeeEncoderMethods(t *testing.T) {
+	input := bigtest.decoded
+	sum := sha256.Sum256([]byte(input))
+	for name, write := range map[string]func(w io.WriteCloser){
+		"WriteString": func(w io.WriteCloser) { w.(io.StringWriter).WriteString(input) },
+		// The reader hides strings.Reader's WriteTo, so io.Copy uses ReadFrom.
+		"ReadFrom": func(w io.WriteCloser) { io.Copy(w, struct{ io.Reader }{strings.NewReader(input)}) },
+		"Flush": func(w io.WriteCloser) {
+			w.Write([]byte(input[:4]))
+			w.(interface{ Flush() error }).Flush()
+			w.Write([]byte(input[4:]))
+		},
+	} {
+		var buf bytes.Buffer
+		h := sha256.New()
+		w := NewTeeEncoder(&buf, h)
+		write(w)
+		if err := w.Close(); err != nil {
+			t.Fatalf("TeeEncoder %s Close() = %v want nil", name, err)
+		}
+		testEqual(t, "TeeEncoder %s digest = %x, want %x", name, string(h.Sum(nil)), string(sum[:]))
+		if name != "Flush" {
+			testEqual(t, "TeeEncoder %s output = %q, want %q", name, buf.String(), bigtest.encoded)
+		}
+	}
+
+	w := NewTeeEncoder(io.Discard, io.Discard)
+	for _, m := range []string{"Flush", "Reset", "Err", "WriteString", "ReadFrom", "MarshalBinary"} {
+		if _, ok := reflect.TypeOf(w).MethodByName(m); !ok {
+			t.Errorf("TeeEncoder has no %s method", m)
+		}
+	}
+}
+
+func TestCountDecoded(t *testing.T) {
+	raw := []byte("\x00ab\x00\x00c\x00")
+	raw = append(raw, make([]byte, 1000)...)
+	raw = append(raw, "xyz"...)
+	total, matched, err := CountDecoded(strings.NewReader(EncodeToString(raw)), func(b byte) bool { return b == 0 })
+	if err != nil {
+		t.Fatalf("CountDecoded failed: %v", err)
+	}
+	testEqual(t, "CountDecoded total = %v, want %v", total, int64(len(raw)))
+	testEqual(t, "CountDecoded matched = %v, want %v", matched, int64(1004))
+
+	total, matched, err = CountDecoded(strings.NewReader("3146755701234568"), func(b byte) bool { return true })
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("Corrupt input error expected.  Found %T (%v)", err, err)
+	}
+	testEqual(t, "CountDecoded total = %v, want %v", total, matched)
+}
+
+func TestPipe(t *testing.T) {
+	enc, dec := Pipe()
+
+	errc := make(chan error, 1)
+	go func() {
+		input := []byte(bigtest.decoded)
+		for pos := 0; pos < len(input); pos += 5 {
+			end := pos + 5
+			if end > len(input) {
+				end = len(input)
+			}
+			if _, err := enc.Write(input[pos:end]); err != nil {
+				errc <- err
+				return
+			}
+		}
+		errc <- enc.Close()
+	}()
+
+	decoded, err := ioutil.ReadAll(dec)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(dec): %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("encoder failed: %v", err)
+	}
+	testEqual(t, "Pipe round trip = %q, want %q", string(decoded), bigtest.decoded)
+}
@@ -0,0 +1,44 @@
+package base8
+
+import (
+	"fmt"
+	"strings"
+)
+
+// decodedLenExact returns the number of bytes that the padded base8 string s
+// decodes to if s is valid. For any s, it is an upper bound on the number of
+// bytes that decode writes.
+func decodedLenExact(s string) int {
+	n := DecodedLen(len(s))
+	if len(s)%8 == 0 {
+		switch {
+		case strings.HasSuffix(s, "====="):
+			n -= 2
+		case strings.HasSuffix(s, "=="):
+			n--
+		}
+	}
+	return n
+}
+
+// DecodeConcat decodes each of chunks, which must be separately padded base8
+// strings, and returns the concatenation of the results. The result is
+// allocated once. If a chunk is invalid, DecodeConcat returns an error that
+// identifies the chunk's index and wraps the underlying CorruptInputError.
+func DecodeConcat(chunks ...string) ([]byte, error) {
+	total := 0
+	for _, c := range chunks {
+		total += decodedLenExact(c)
+	}
+
+	buf := make([]byte, total)
+	n := 0
+	for i, c := range chunks {
+		nn, _, err := decode(StdEncoding, buf[n:], c)
+		n += nn
+		if err != nil {
+			return buf[:n], fmt.Errorf("base8: decoding chunk %d: %w", i, err)
+		}
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,56 @@
+package base8
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeConcat(t *testing.T) {
+	parts := []string{"foob", "", "ar", bigtest.decoded, "foo"}
+	var chunks []string
+	for _, p := range parts {
+		chunks = append(chunks, EncodeToString([]byte(p)))
+	}
+	got, err := DecodeConcat(chunks...)
+	if err != nil {
+		t.Fatalf("DecodeConcat(%q) failed: %v", chunks, err)
+	}
+	want := strings.Join(parts, "")
+	testEqual(t, "DecodeConcat(%q) = %q, want %q", chunks, string(got), want)
+	testEqual(t, "cap(DecodeConcat(%q)) = %v, want %v", chunks, cap(got), len(want))
+
+	got, err = DecodeConcat()
+	if err != nil || len(got) != 0 {
+		t.Errorf("DecodeConcat() = %q, %v; want empty", got, err)
+	}
+}
+
+func TestDecodeConcatCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		bad    string
+		offset int
+	}{
+		{"3146755!", 7},
+		{"314====", 7},
+		{"314=", 4},
+		{"31467557=====", 8},
+		{"3146755=", 7},
+		{"31467557314=====31467557", 11},
+	} {
+		_, err := DecodeConcat("31467557", tc.bad, "30460562")
+		if err == nil || !strings.Contains(err.Error(), "chunk 1") {
+			t.Errorf("DecodeConcat error = %v, want mention of chunk 1", err)
+		}
+		var cie CorruptInputError
+		if !errors.As(err, &cie) || int(cie.Offset()) != tc.offset {
+			t.Errorf("DecodeConcat error = %v, want CorruptInputError(%d)", err, tc.offset)
+		}
+
+		// A corrupt final chunk must not write past the end of the result.
+		_, err = DecodeConcat("31467557", tc.bad)
+		if !errors.As(err, &cie) || int(cie.Offset()) != tc.offset {
+			t.Errorf("DecodeConcat error = %v, want CorruptInputError(%d)", err, tc.offset)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package base8
+
+import "crypto/subtle"
+
+/*
+ * Constant-time variants
+ *
+ * The functions below are intended for encoding and decoding secret material
+ * such as keys. They defend against an attacker who can measure how long an
+ * operation takes or observe which memory it touches (for example through a
+ * shared CPU cache), and who wants to learn the secret from those timings.
+ * The standard Encode and Decode index tables and branch on the values of the
+ * bytes they process, which can leak information to such an attacker.
+ *
+ * The constant-time variants make no secret-dependent branches or table
+ * lookups, so their timing and memory access pattern depend only on the
+ * length of their input and output. They are slower than the standard
+ * functions. They do not hide the length of the data, nor whether the input
+ * was valid: invalid input is re-decoded by Decode in order to report the same
+ * error it would.
+ */
+
+// ConstantTimeEncode is like Encode, but computes each digit arithmetically
+// rather than by table lookup, so that its timing and memory access pattern
+// depend only on the length of src.
+func ConstantTimeEncode(dst, src []byte) {
+	for len(src) > 0 {
+		var b [8]byte
+		var q [3]byte
+		n := copy(q[0:], src)
+
+		b[0] = q[0] >> 5
+		b[1] = q[0] >> 2 & 7
+		b[2] = (q[0]<<1 | q[1]>>7) & 7
+		b[3] = q[1] >> 4 & 7
+		b[4] = q[1] >> 1 & 7
+		b[5] = (q[1]<<2 | q[2]>>6) & 7
+		b[6] = q[2] >> 3 & 7
+		b[7] = q[2] & 7
+		for i := range b {
+			dst[i] = '0' + b[i]
+		}
+
+		// Pad the final quantum
+		if n < 3 {
+			dst[7] = PadChar
+			dst[6] = PadChar
+			if n < 2 {
+				dst[5] = PadChar
+				dst[4] = PadChar
+				dst[3] = PadChar
+			}
+			break
+		}
+
+		src = src[3:]
+		dst = dst[8:]
+	}
+}
+
+// ConstantTimeDecode is like Decode, but decodes valid input in time that
+// depends only on the length of src. Input whose length is not a multiple of
+// 8 is never valid standard base8 and is passed to Decode directly.
+func ConstantTimeDecode(dst, src []byte) (int, error) {
+	if len(src)%8 != 0 {
+		return Decode(dst, src)
+	}
+
+	valid, n := 1, 0
+	for i := 0; i < len(src); i += 8 {
+		q := src[i : i+8]
+		var dbuf [8]byte
+		var digit, pad [8]int
+		for j := 0; j < 8; j++ {
+			v := q[j] ^ '0'
+			digit[j] = subtle.ConstantTimeByteEq(v&0xf8, 0)
+			pad[j] = subtle.ConstantTimeByteEq(q[j], PadChar)
+			dbuf[j] = v & 7 & byte(-digit[j])
+		}
+
+		var out [3]byte
+		out[0] = dbuf[0]<<5 | dbuf[1]<<2 | dbuf[2]>>1
+		out[1] = dbuf[2]<<7 | dbuf[3]<<4 | dbuf[4]<<1 | dbuf[5]>>2
+		out[2] = dbuf[5]<<6 | dbuf[6]<<3 | dbuf[7]
+
+		full := digit[0] & digit[1] & digit[2] & digit[3] & digit[4] & digit[5] & digit[6] & digit[7]
+		count := 3
+		if i+8 < len(src) {
+			// Only the final quantum may be padded.
+			valid &= full
+		} else {
+			three := digit[0] & digit[1] & digit[2] & pad[3] & pad[4] & pad[5] & pad[6] & pad[7]
+			six := digit[0] & digit[1] & digit[2] & digit[3] & digit[4] & digit[5] & pad[6] & pad[7]
+			valid &= full | three | six
+			count = 3*full + three + 2*six
+		}
+		n += copy(dst[n:], out[0:count])
+	}
+
+	if valid == 0 {
+		return Decode(dst, src)
+	}
+	return n, nil
+}
@@ -0,0 +1,61 @@
+package base8
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConstantTimeEncode(t *testing.T) {
+	inputs := []string{bigtest.decoded, "\x00\xff\x80\x7f\x01"}
+	for _, p := range pairs {
+		inputs = append(inputs, p.decoded)
+	}
+	for i := 0; i < 256; i++ {
+		inputs = append(inputs, string([]byte{byte(i), byte(255 - i), byte(i * 7)}))
+	}
+	for _, in := range inputs {
+		want := make([]byte, EncodedLen(len(in)))
+		Encode(want, []byte(in))
+		got := make([]byte, EncodedLen(len(in)))
+		ConstantTimeEncode(got, []byte(in))
+		testEqual(t, "ConstantTimeEncode(%q) = %q, want %q", in, string(got), string(want))
+	}
+}
+
+func TestConstantTimeDecode(t *testing.T) {
+	inputs := []string{bigtest.encoded}
+	for _, p := range pairs {
+		inputs = append(inputs, p.encoded)
+	}
+	inputs = append(inputs,
+		"!!!!",
+		"1=======",
+		"11======",
+		"1111====",
+		"11=1====",
+		"111=1111",
+		"222222222",
+		"314=====31467557",
+		"31467557314=====",
+		"3146755831467557",
+		"111======",
+	)
+	for _, in := range inputs {
+		want := make([]byte, DecodedLen(len(in))+3)
+		wantN, wantErr := Decode(want, []byte(in))
+		got := make([]byte, DecodedLen(len(in))+3)
+		gotN, gotErr := ConstantTimeDecode(got, []byte(in))
+		testEqual(t, "ConstantTimeDecode(%q) = error %v, want %v", in, gotErr, wantErr)
+		testEqual(t, "ConstantTimeDecode(%q) = length %v, want %v", in, gotN, wantN)
+		if !bytes.Equal(got[:gotN], want[:wantN]) {
+			t.Errorf("ConstantTimeDecode(%q) = %q, want %q", in, got[:gotN], want[:wantN])
+		}
+	}
+
+	// The destination need only hold the decoded bytes.
+	dst := make([]byte, 1)
+	n, err := ConstantTimeDecode(dst, []byte("314====="))
+	if err != nil || n != 1 || dst[0] != 'f' {
+		t.Errorf("ConstantTimeDecode(%q) = %q, %v", "314=====", dst[:n], err)
+	}
+}
@@ -0,0 +1,60 @@
+package base8
+
+import (
+	"context"
+	"io"
+)
+
+// contextWriter fails writes to w once ctx is done.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (c contextWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+// contextReader fails reads from r once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// NewEncoderContext is like NewEncoder, but checks ctx before each write of
+// encoded data to w, which happens once per buffer of output. Once ctx is
+// done, the pending write fails with ctx.Err() and, as with any error from w,
+// that error is returned from all subsequent calls to Write and Close. This
+// lets a long-running encode be aborted between chunks.
+func (enc *Encoding) NewEncoderContext(ctx context.Context, w io.Writer) io.WriteCloser {
+	return enc.NewEncoder(contextWriter{ctx: ctx, w: w})
+}
+
+// NewEncoderContext is like NewEncoder, but stops writing to w once ctx is
+// done.
+func NewEncoderContext(ctx context.Context, w io.Writer) io.WriteCloser {
+	return StdEncoding.NewEncoderContext(ctx, w)
+}
+
+// NewDecoderContext is like NewDecoder, but checks ctx before each read of
+// encoded data from r, which happens once per buffer of input. Once ctx is
+// done, Read returns ctx.Err() after any data already decoded.
+func (enc *Encoding) NewDecoderContext(ctx context.Context, r io.Reader) io.Reader {
+	return enc.NewDecoder(contextReader{ctx: ctx, r: r})
+}
+
+// NewDecoderContext is like NewDecoder, but stops reading from r once ctx is
+// done.
+func NewDecoderContext(ctx context.Context, r io.Reader) io.Reader {
+	return StdEncoding.NewDecoderContext(ctx, r)
+}
@@ -0,0 +1,54 @@
+package base8
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncoderContext(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewEncoderContext(context.Background(), &buf)
+	w.Write([]byte(bigtest.decoded))
+	if err := w.Close(); err != nil {
+		t.Fatalf("NewEncoderContext.Close() = %v, want nil", err)
+	}
+	testEqual(t, "NewEncoderContext(%q) = %q, want %q", bigtest.decoded, buf.String(), bigtest.encoded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	buf.Reset()
+	w = NewEncoderContext(ctx, &buf)
+	w.Write([]byte("foobar"))
+	cancel()
+	if _, err := w.Write([]byte("foobar")); err != context.Canceled {
+		t.Errorf("NewEncoderContext Write after cancel = %v, want %v", err, context.Canceled)
+	}
+	if err := w.Close(); err != context.Canceled {
+		t.Errorf("NewEncoderContext Close after cancel = %v, want %v", err, context.Canceled)
+	}
+	testEqual(t, "NewEncoderContext output before cancel = %q, want %q", buf.String(), "3146755730460562")
+}
+
+func TestDecoderContext(t *testing.T) {
+	got, err := io.ReadAll(NewDecoderContext(context.Background(), strings.NewReader(bigtest.encoded)))
+	testEqual(t, "NewDecoderContext = error %v, want %v", err, error(nil))
+	testEqual(t, "NewDecoderContext = %q, want %q", string(got), bigtest.decoded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	input := strings.Repeat("31467557", 256)
+	r := NewDecoderContext(ctx, strings.NewReader(input))
+	var p [3]byte
+	if n, err := r.Read(p[0:]); n != 3 || err != nil {
+		t.Fatalf("NewDecoderContext Read = %d, %v, want %d, %v", n, err, 3, error(nil))
+	}
+	cancel()
+
+	// Data decoded before cancellation is still returned.
+	got, err = io.ReadAll(r)
+	testEqual(t, "NewDecoderContext Read after cancel = %v, want %v", err, context.Canceled)
+	if len(got) >= len(input)/8*3-3 {
+		t.Errorf("NewDecoderContext decoded %d bytes after cancel, want fewer than %d", len(got), len(input)/8*3-3)
+	}
+}
@@ -0,0 +1,71 @@
+package base8
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+var errMalformedDataURI = errors.New("base8: malformed data URI")
+
+type dataURIEncoder struct {
+	err    error
+	w      io.Writer
+	prefix string
+	enc    io.WriteCloser
+}
+
+// start writes the data URI prefix if it has not been written yet.
+func (e *dataURIEncoder) start() error {
+	if e.err == nil && e.enc == nil {
+		_, e.err = io.WriteString(e.w, e.prefix)
+		e.enc = NewEncoder(e.w)
+	}
+	return e.err
+}
+
+func (e *dataURIEncoder) Write(p []byte) (n int, err error) {
+	if err := e.start(); err != nil {
+		return 0, err
+	}
+	return e.enc.Write(p)
+}
+
+// Close flushes any pending output from the encoder, writing the data URI
+// prefix first if nothing has been written yet.
+func (e *dataURIEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.enc.Close()
+}
+
+// NewDataURIEncoder returns a new base8 stream encoder that writes a data URI
+// of the form "data:<mimeType>;base8,<data>" to w. The prefix is written
+// before the first encoded data; after Close, w holds a complete data URI
+// even if no data was written. base8 is not a standard data URI encoding, so
+// such URIs are only meaningful to DecodeDataURI and similar tools.
+func NewDataURIEncoder(w io.Writer, mimeType string) io.WriteCloser {
+	return &dataURIEncoder{w: w, prefix: "data:" + mimeType + ";base8,"}
+}
+
+// DecodeDataURI parses a data URI produced by NewDataURIEncoder and returns
+// its MIME type and decoded data.
+func DecodeDataURI(s string) (mimeType string, data []byte, err error) {
+	rest, ok := strings.CutPrefix(s, "data:")
+	if !ok {
+		return "", nil, errMalformedDataURI
+	}
+	mimeType, encoded, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, errMalformedDataURI
+	}
+	if mimeType, ok = strings.CutSuffix(mimeType, ";base8"); !ok {
+		return "", nil, errMalformedDataURI
+	}
+	data, err = DecodeString(encoded)
+	if err != nil {
+		return "", nil, err
+	}
+	return mimeType, data, nil
+}
@@ -0,0 +1,60 @@
+package base8
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataURI(t *testing.T) {
+	for _, tc := range []struct {
+		mimeType, data string
+	}{
+		{"text/plain", bigtest.decoded},
+		{"application/octet-stream", "\x00\x01\xff"},
+		{"text/plain;charset=utf-8", "foo"},
+		{"", ""},
+	} {
+		var b strings.Builder
+		w := NewDataURIEncoder(&b, tc.mimeType)
+		for i := 0; i < len(tc.data); i += 5 {
+			end := i + 5
+			if end > len(tc.data) {
+				end = len(tc.data)
+			}
+			w.Write([]byte(tc.data[i:end]))
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		uri := b.String()
+		want := "data:" + tc.mimeType + ";base8," + EncodeToString([]byte(tc.data))
+		testEqual(t, "data URI = %q, want %q", uri, want)
+
+		mimeType, data, err := DecodeDataURI(uri)
+		if err != nil {
+			t.Errorf("DecodeDataURI(%q) failed: %v", uri, err)
+			continue
+		}
+		testEqual(t, "DecodeDataURI(%q) MIME type = %q, want %q", uri, mimeType, tc.mimeType)
+		testEqual(t, "DecodeDataURI(%q) data = %q, want %q", uri, string(data), tc.data)
+	}
+}
+
+func TestDecodeDataURIMalformed(t *testing.T) {
+	for _, uri := range []string{
+		"",
+		"314=====",
+		"data:text/plain,314=====",
+		"data:text/plain;base64,314=====",
+		"data:text/plain;base8",
+		"date:text/plain;base8,314=====",
+	} {
+		if _, _, err := DecodeDataURI(uri); err != errMalformedDataURI {
+			t.Errorf("DecodeDataURI(%q) = %v, want %v", uri, err, errMalformedDataURI)
+		}
+	}
+
+	_, _, err := DecodeDataURI("data:text/plain;base8,31!=====")
+	testEqual(t, "DecodeDataURI(corrupt) = %v, want %v", corruptOffset(err), int64(2))
+}
@@ -0,0 +1,33 @@
+package base8
+
+// DecodeMap returns the mapping from each byte that enc's decoder accepts as
+// a symbol to the 3-bit value it represents, for tools that display or
+// explain an encoding. It includes both cases of the letters of a
+// CaseInsensitive encoding. The map is a copy, so modifying it does not
+// affect enc; the padding character and ignored characters, which are not
+// symbols, are reported by SpecialBytes.
+func (enc *Encoding) DecodeMap() map[byte]byte {
+	m := make(map[byte]byte, len(enc.encode))
+	for i, v := range enc.decodeMap {
+		if v <= 7 {
+			m[byte(i)] = v
+		}
+	}
+	return m
+}
+
+// SpecialBytes returns the bytes that enc's decoder accepts without treating
+// them as symbols, in ascending order: pad holds the padding character, if
+// the encoding has one, and ignored holds the characters set by
+// WithIgnoredChars. Every other byte that DecodeMap omits is rejected.
+func (enc *Encoding) SpecialBytes() (pad, ignored []byte) {
+	for i, v := range enc.decodeMap {
+		switch v {
+		case paddingIndex:
+			pad = append(pad, byte(i))
+		case ignoredIndex:
+			ignored = append(ignored, byte(i))
+		}
+	}
+	return pad, ignored
+}
@@ -0,0 +1,33 @@
+package base8
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestEncodingDecodeMap(t *testing.T) {
+	m := StdEncoding.DecodeMap()
+	testEqual(t, "len(DecodeMap()) = %v, want %v", len(m), 8)
+	for c := byte('0'); c <= '7'; c++ {
+		v, ok := m[c]
+		testEqual(t, "DecodeMap()[%q] present = %v, want %v", c, ok, true)
+		testEqual(t, "DecodeMap()[%q] = %v, want %v", c, v, c-'0')
+	}
+	pad, ignored := StdEncoding.SpecialBytes()
+	testEqual(t, "SpecialBytes() pad = %q, want %q", string(pad), "=")
+	testEqual(t, "SpecialBytes() ignored = %q, want %q", string(ignored), "")
+
+	// The map is a copy.
+	m['8'] = 0
+	testEqual(t, "len(DecodeMap()) = %v, want %v", len(StdEncoding.DecodeMap()), 8)
+
+	pad, _ = RawEncoding.SpecialBytes()
+	testEqual(t, "RawEncoding.SpecialBytes() pad = %q, want %q", string(pad), "")
+
+	_, ignored = StdEncoding.WithIgnoredChars("\n \t").SpecialBytes()
+	testEqual(t, "SpecialBytes() ignored = %q, want %q", string(ignored), "\t\n ")
+
+	keys := slices.Sorted(maps.Keys(LetterEncoding.CaseInsensitive().DecodeMap()))
+	testEqual(t, "CaseInsensitive().DecodeMap() keys = %q, want %q", string(keys), "ABCDEFGHabcdefgh")
+}
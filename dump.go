@@ -0,0 +1,43 @@
+package base8
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dumpRowLen is the number of bytes shown on each row of DumpTable. It is a
+// multiple of 3 so that each row's encoding consists of whole quanta.
+const dumpRowLen = 6
+
+// DumpTable returns a table describing data for use in format documentation.
+// Each row covers up to 6 bytes and shows the decimal offset of its first
+// byte, the bytes as 3-digit octal numbers, the base8 encoding of the bytes
+// and their ASCII rendering, with '.' for non-printable bytes. Columns are
+// aligned and the final row may be partial, in which case its encoding is
+// padded.
+func DumpTable(data []byte) string {
+	const octalWidth = dumpRowLen*4 - 1
+	const base8Width = dumpRowLen / 3 * 8
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%8s  %-*s  %-*s  %s\n", "offset", octalWidth, "octal", base8Width, "base8", "ascii")
+	for off := 0; off < len(data); off += dumpRowLen {
+		row := data[off:]
+		if len(row) > dumpRowLen {
+			row = row[:dumpRowLen]
+		}
+
+		octal := make([]string, len(row))
+		ascii := make([]byte, len(row))
+		for i, c := range row {
+			octal[i] = fmt.Sprintf("%03o", c)
+			ascii[i] = '.'
+			if c >= 0x20 && c < 0x7f {
+				ascii[i] = c
+			}
+		}
+
+		fmt.Fprintf(&b, "%8d  %-*s  %-*s  |%s|\n", off, octalWidth, strings.Join(octal, " "), base8Width, EncodeToString(row), ascii)
+	}
+	return b.String()
+}
@@ -0,0 +1,19 @@
+package base8
+
+import "testing"
+
+func TestDumpTable(t *testing.T) {
+	got := DumpTable([]byte("foobar\x00\x01Twas\xff"))
+	want := "" +
+		"  offset  octal                    base8             ascii\n" +
+		"       0  146 157 157 142 141 162  3146755730460562  |foobar|\n" +
+		"       6  000 001 124 167 141 163  0000052435660563  |..Twas|\n" +
+		"      12  377                      776=====          |.|\n"
+	if got != want {
+		t.Errorf("DumpTable =\n%s\nwant\n%s", got, want)
+	}
+
+	got = DumpTable(nil)
+	want = "  offset  octal                    base8             ascii\n"
+	testEqual(t, "DumpTable(nil) = %q, want %q", got, want)
+}
@@ -0,0 +1,140 @@
+package base8
+
+// WithLengthEscape creates a new encoding identical to enc except that a
+// short final quantum is escaped rather than padded, for formats that forbid
+// both trailing padding and unmarked short quanta. The escape is the
+// encoding's padding character, which must not be NoPadding.
+//
+// The output of such an encoding is a sequence of whole 8-symbol quanta,
+// each of which holds 3 bytes, optionally followed by an escaped tail:
+//
+//	output = { quantum } [ tail ]
+//	tail   = escape ( symbol(1) 3*symbol | symbol(2) 6*symbol )
+//
+// where symbol(n) is the symbol whose value is n. The tail holds the 1 or 2
+// bytes left over once the input is split into groups of 3, encoded as the
+// first 3 or 6 symbols of a padded quantum would be, so it is 5 or 8 bytes
+// long. Because the escape is not a symbol and may only begin the tail, it
+// cannot collide with data, and the count that follows it tells the decoder
+// where the input ends without any padding or out-of-band length. For
+// example, StdEncoding.WithLengthEscape() encodes "\x01\x02\x03\x04" as
+// "00201003=1010".
+//
+// Every encoder understands the escape. So do the decoders that see the
+// whole input, or can tell where it ends: Decode, DecodeString,
+// DecodeStringInto, MustDecodeString, AppendDecode, DecodeConsumed,
+// DecodeStringWholeQuanta, DecodeGroups, DecodeParallel, DecodeRecover,
+// DecodeSeq, Validate, IsValid, Compare, NewDecodingWriter and NewDecoderAt.
+// The stream decoders panic for such an encoding: NewDecoder, NewDecoderSize
+// and those built on them (NewDecoderCloser, NewDecoderContext,
+// NewWrappingDecoder, NewSeekableDecoder, NewSeekableWrappingDecoder and
+// Pipe), as well as NewMessageDecoder.
+func (enc Encoding) WithLengthEscape() *Encoding {
+	if enc.padChar == NoPadding {
+		panic("length escape requires a padding character")
+	}
+	enc.escape = true
+	return &enc
+}
+
+// escapeStart returns the offset of the escaped tail of src, or len(src) if
+// src has none: the tail is whatever follows the last whole quantum, or the
+// last quantum if it begins with the escape.
+func escapeStart[T ~string | ~[]byte](enc *Encoding, src T) int {
+	k := len(src) / 8 * 8
+	if k == len(src) && k > 0 && enc.decodeMap[src[k-8]] == paddingIndex {
+		k -= 8
+	}
+	return k
+}
+
+// firstNonSymbol returns the offset of the first byte of src that is not a
+// symbol, or len(src) if there is none.
+func firstNonSymbol[T ~string | ~[]byte](enc *Encoding, src T) int {
+	for i := 0; i < len(src); i++ {
+		if enc.decodeMap[src[i]] > 7 {
+			return i
+		}
+	}
+	return len(src)
+}
+
+// decodeEscaped implements decode for encodings with a length escape and
+// input that contains no ignored characters.
+func decodeEscaped[T ~string | ~[]byte](enc *Encoding, dst []byte, src T) (n int, end bool, err error) {
+	k := escapeStart(enc, src)
+	n, end, err = decodeQuanta(enc, dst, src[0:k])
+	if err != nil || end {
+		// The quanta before the tail hold only symbols, so anything that
+		// decodeQuanta took for padding or rejected is an invalid symbol.
+		if i := firstNonSymbol(enc, src[0:k]); i < k {
+			return i / 8 * 3, false, enc.invalidSymbol(i, src[i])
+		}
+		return n, false, err
+	}
+	if k == len(src) {
+		return n, false, nil
+	}
+	nn, err := decodeEscapedTail(enc, dst[n:], src, k)
+	return n + nn, err == nil, err
+}
+
+// validateEscaped implements validate for encodings with a length escape and
+// input that contains no ignored characters.
+func validateEscaped[T ~string | ~[]byte](enc *Encoding, src T) error {
+	k := escapeStart(enc, src)
+	if i := firstNonSymbol(enc, src[0:k]); i < k {
+		return enc.invalidSymbol(i, src[i])
+	}
+	if k == len(src) {
+		return nil
+	}
+	var dbuf [2]byte
+	_, err := decodeEscapedTail(enc, dbuf[0:], src, k)
+	return err
+}
+
+// decodeEscapedTail decodes the escaped tail that begins at offset k of src
+// into dst, which must have room for the 1 or 2 bytes it holds.
+func decodeEscapedTail[T ~string | ~[]byte](enc *Encoding, dst []byte, src T, k int) (int, error) {
+	tail := src[k:]
+	switch {
+	case enc.decodeMap[tail[0]] != paddingIndex:
+		// The input ends with a partial quantum that is not escaped.
+		if enc.decodeMap[tail[0]] > 7 {
+			return 0, enc.invalidSymbol(k, tail[0])
+		}
+		return 0, truncated(k, ExpectPadding)
+	case len(tail) == 1:
+		return 0, truncated(k, ExpectSymbol)
+	}
+	count := int(enc.decodeMap[tail[1]])
+	if count != 1 && count != 2 {
+		return 0, enc.invalidSymbol(k+1, tail[1])
+	}
+
+	want := 2 + count*3
+	for i := 2; i < len(tail) && i < want; i++ {
+		if enc.decodeMap[tail[i]] > 7 {
+			return 0, enc.invalidSymbol(k+i, tail[i])
+		}
+	}
+	switch {
+	case len(tail) < want:
+		return 0, truncated(k, ExpectSymbol)
+	case len(tail) > want:
+		return 0, trailing(k+want, tail[want])
+	}
+
+	// Decode the symbols as the padded quantum they were taken from.
+	var q [8]byte
+	for i := range q {
+		q[i] = byte(enc.padChar)
+	}
+	copy(q[0:], tail[2:want])
+	n, _, err := decodeQuanta(enc, dst, q[0:])
+	if err != nil {
+		return 0, err.(CorruptInputError).shift(int64(k + 2))
+	}
+	return n, nil
+}
@@ -0,0 +1,121 @@
+package base8
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLengthEscape(t *testing.T) {
+	enc := StdEncoding.WithLengthEscape()
+	for _, p := range []struct {
+		decoded, encoded string
+	}{
+		{"", ""},
+		{"\x01", "=1002"},
+		{"\x01\x02", "=2002010"},
+		{"\x01\x02\x03", "00201003"},
+		{"\x01\x02\x03\x04", "00201003=1010"},
+		{"\x01\x02\x03\x04\x05", "00201003=2010024"},
+		{"\xff\xff\xff\xff\xff\xff", "7777777777777777"},
+	} {
+		got := enc.EncodeToString([]byte(p.decoded))
+		testEqual(t, "EncodeToString(%q) = %q, want %q", p.decoded, got, p.encoded)
+		testEqual(t, "EncodedLen(%d) = %d, want %d", len(p.decoded), enc.EncodedLen(len(p.decoded)), len(p.encoded))
+		testEqual(t, "EncodedLen64(%d) = %d, want %d", len(p.decoded), enc.EncodedLen64(int64(len(p.decoded))), int64(len(p.encoded)))
+		testEqual(t, "DecodedLen(%d) >= %d = %v, want %v", len(p.encoded), len(p.decoded), enc.DecodedLen(len(p.encoded)) >= len(p.decoded), true)
+	}
+
+	for _, base := range []*Encoding{StdEncoding, LetterEncoding, StdEncoding.WithBitOrder(LSBFirst), StdEncoding.WithPadding('*')} {
+		esc := base.WithLengthEscape()
+		for _, p := range append(pairs, bigtest) {
+			encoded := esc.EncodeToString([]byte(p.decoded))
+			if i := strings.IndexRune(encoded, base.padChar); i >= 0 && i != len(p.decoded)/3*8 {
+				t.Errorf("EncodeToString(%q) = %q has an escape at offset %d", p.decoded, encoded, i)
+			}
+
+			dbuf, err := esc.DecodeString(encoded)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", encoded, string(dbuf), p.decoded)
+
+			dbuf = make([]byte, esc.DecodedLen(len(encoded)))
+			n, err := esc.DecodeParallel(dbuf, []byte(encoded))
+			testEqual(t, "DecodeParallel(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "DecodeParallel(%q) = %q, want %q", encoded, string(dbuf[:n]), p.decoded)
+
+			n, err = esc.Validate([]byte(encoded))
+			testEqual(t, "Validate(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "Validate(%q) = %v, want %v", encoded, n, len(encoded))
+
+			var buf bytes.Buffer
+			w := esc.NewEncoder(&buf)
+			w.Write([]byte(p.decoded))
+			w.Close()
+			testEqual(t, "NewEncoder(%q) = %q, want %q", p.decoded, buf.String(), encoded)
+
+			// Ignored characters may surround the escape.
+			ign := esc.WithIgnoredChars("\n")
+			wrapped := wrap(encoded, 1, "\n")
+			dbuf, err = ign.DecodeString(wrapped)
+			testEqual(t, "DecodeString(%q) = error %v, want %v", wrapped, err, error(nil))
+			testEqual(t, "DecodeString(%q) = %q, want %q", wrapped, string(dbuf), p.decoded)
+		}
+	}
+}
+
+func TestLengthEscapeErrors(t *testing.T) {
+	enc := StdEncoding.WithLengthEscape()
+	for _, tc := range []struct {
+		input  string
+		offset int64
+		err    error
+	}{
+		{"002", 0, ErrInvalidLength}, // unescaped short quantum
+		{"00201003002010", 8, ErrInvalidLength},
+		{"=", 0, ErrInvalidLength},        // missing count
+		{"=100", 0, ErrInvalidLength},     // too few symbols
+		{"=2002", 0, ErrInvalidLength},    // count of 2 with 3 symbols
+		{"=0002", 1, ErrInvalidCharacter}, // count must be 1 or 2
+		{"=3002", 1, ErrInvalidCharacter},
+		{"==002", 1, ErrInvalidPadding},
+		{"=10=2", 3, ErrInvalidPadding},
+		{"=100x", 4, ErrInvalidCharacter},
+		{"=1002010", 5, ErrInvalidPadding}, // data after the tail
+		{"002=====", 3, ErrInvalidPadding}, // padding is not accepted
+		{"002=====00201003", 3, ErrInvalidPadding},
+		{"=100200201003", 0, ErrInvalidPadding}, // the escape may only begin the tail
+		{"0020=003", 4, ErrInvalidPadding},
+		{"00201003=1x02", 10, ErrInvalidCharacter},
+	} {
+		_, err := enc.DecodeString(tc.input)
+		testEqual(t, "DecodeString(%q) offset = %v, want %v", tc.input, corruptOffset(err), tc.offset)
+		if err, ok := err.(CorruptInputError); ok {
+			testEqual(t, "DecodeString(%q) error = %v, want %v", tc.input, err.err, tc.err)
+		}
+
+		n, verr := enc.Validate([]byte(tc.input))
+		testEqual(t, "Validate(%q) = %v, want %v", tc.input, verr, err)
+		testEqual(t, "Validate(%q) = %v, want %v", tc.input, int64(n), tc.offset)
+	}
+}
+
+func TestLengthEscapePanics(t *testing.T) {
+	for _, f := range []func(){
+		func() { RawEncoding.WithLengthEscape() },
+		func() { StdEncoding.WithLengthEscape().WithPadding(NoPadding) },
+		func() { StdEncoding.WithLengthEscape().NewDecoder(strings.NewReader("")) },
+		func() { StdEncoding.WithLengthEscape().NewMessageDecoder(strings.NewReader("")) },
+		func() { StdEncoding.WithLengthEscape().NewSeekableDecoder(strings.NewReader("")) },
+		func() { StdEncoding.WithLengthEscape().NewWrappingDecoder(strings.NewReader(""), "\n") },
+		func() { StdEncoding.WithLengthEscape().Pipe() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
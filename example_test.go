@@ -9,7 +9,7 @@ import (
 
 func ExampleEncoding_EncodeToString() {
 	data := []byte("any + old & data")
-	str := base8.EncodeToString(data)
+	str := base8.StdEncoding.EncodeToString(data)
 	fmt.Println(str)
 	// Output:
 	// 3026717110025440336661441002304031060564302=====
@@ -17,7 +17,7 @@ func ExampleEncoding_EncodeToString() {
 
 func ExampleEncoding_DecodeString() {
 	str := "3466755531220144302721411007355135064040000201413346204073735677"
-	data, err := base8.DecodeString(str)
+	data, err := base8.StdEncoding.DecodeString(str)
 	if err != nil {
 		fmt.Println("error:", err)
 		return
@@ -27,6 +27,27 @@ func ExampleEncoding_DecodeString() {
 	// "some data with \x00 and \ufeff"
 }
 
+func ExampleEncodeToString() {
+	data := []byte("any + old & data")
+	fmt.Println(base8.EncodeToString(data))
+	fmt.Println(base8.RawEncoding.EncodeToString(data))
+	// Output:
+	// 3026717110025440336661441002304031060564302=====
+	// 3026717110025440336661441002304031060564302
+}
+
+func ExampleEncoding_WithPadding() {
+	enc := base8.StdEncoding.WithPadding('~')
+	str := enc.EncodeToString([]byte("f"))
+	fmt.Println(str)
+	if _, err := enc.DecodeString("314====="); err != nil {
+		fmt.Println("error:", err)
+	}
+	// Output:
+	// 314~~~~~
+	// error: illegal base8 data at input byte 3
+}
+
 func ExampleNewEncoder() {
 	input := []byte("foo\x00bar")
 	encoder := base8.NewEncoder(os.Stdout)
@@ -1,3 +1,3 @@
 module github.com/pgavlin/base8
 
-go 1.15
+go 1.23
@@ -0,0 +1,75 @@
+package base8
+
+import "strings"
+
+// EncodeToGroups returns the base8 encoding of src split into groups of size
+// characters separated by sep, such as "31467557 30460562" or
+// "3146-7557-3046-0562", for human transcription. The final group may be
+// shorter than size. sep must not contain symbols of the encoding's alphabet
+// or its padding character, and size must be positive. The result can be
+// decoded with DecodeGroups.
+func (enc *Encoding) EncodeToGroups(src []byte, size int, sep string) string {
+	if size <= 0 {
+		panic("invalid group size")
+	}
+	encoded := enc.EncodeToString(src)
+	if len(encoded) <= size {
+		return encoded
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(encoded) + (len(encoded)-1)/size*len(sep))
+	for len(encoded) > size {
+		sb.WriteString(encoded[:size])
+		sb.WriteString(sep)
+		encoded = encoded[size:]
+	}
+	sb.WriteString(encoded)
+	return sb.String()
+}
+
+// EncodeToGroups returns the standard base8 encoding of src split into groups
+// of size characters separated by sep.
+func EncodeToGroups(src []byte, size int, sep string) string {
+	return StdEncoding.EncodeToGroups(src, size, sep)
+}
+
+// DecodeGroups decodes s after removing every occurrence of sep. It is
+// tolerant of the way s was grouped: groups may have any size, and
+// separators may be missing or repeated. The offset of a CorruptInputError it
+// returns locates the offending byte in s, counting the separators.
+func (enc *Encoding) DecodeGroups(s, sep string) ([]byte, error) {
+	if sep == "" || !strings.Contains(s, sep) {
+		return enc.DecodeString(s)
+	}
+	dbuf, err := enc.DecodeString(strings.ReplaceAll(s, sep, ""))
+	if err != nil {
+		e := err.(CorruptInputError)
+		return dbuf, e.shift(ungroupOffset(s, sep, e.offset) - e.offset)
+	}
+	return dbuf, nil
+}
+
+// DecodeGroups decodes the standard base8 string s after removing every
+// occurrence of sep.
+func DecodeGroups(s, sep string) ([]byte, error) {
+	return StdEncoding.DecodeGroups(s, sep)
+}
+
+// ungroupOffset converts an offset into s with every occurrence of sep
+// removed into the corresponding offset into s.
+func ungroupOffset(s, sep string, off int64) int64 {
+	i := 0
+	for i < len(s) {
+		if strings.HasPrefix(s[i:], sep) {
+			i += len(sep)
+			continue
+		}
+		if off == 0 {
+			break
+		}
+		off--
+		i++
+	}
+	return int64(i)
+}
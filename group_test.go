@@ -0,0 +1,66 @@
+package base8
+
+import "testing"
+
+func TestEncodeToGroups(t *testing.T) {
+	testCases := []struct {
+		decoded string
+		size    int
+		sep     string
+		encoded string
+	}{
+		{"", 8, " ", ""},
+		{"foo", 8, " ", "31467557"},
+		{"foobar", 8, " ", "31467557 30460562"},
+		{"foobar", 4, "-", "3146-7557-3046-0562"},
+		{"foob", 4, "-", "3146-7557-304=-===="},
+		{"foob", 5, " - ", "31467 - 55730 - 4==== - ="},
+	}
+	for _, tc := range testCases {
+		got := EncodeToGroups([]byte(tc.decoded), tc.size, tc.sep)
+		testEqual(t, "EncodeToGroups(%q, %d, %q) = %q, want %q", tc.decoded, tc.size, tc.sep, got, tc.encoded)
+
+		dbuf, err := DecodeGroups(got, tc.sep)
+		testEqual(t, "DecodeGroups(%q, %q) = error %v, want %v", got, tc.sep, err, error(nil))
+		testEqual(t, "DecodeGroups(%q, %q) = %q, want %q", got, tc.sep, string(dbuf), tc.decoded)
+	}
+
+	got := RawEncoding.EncodeToGroups([]byte("foob"), 4, "-")
+	testEqual(t, "RawEncoding.EncodeToGroups(%q) = %q, want %q", "foob", got, "3146-7557-304")
+}
+
+func TestDecodeGroupsTolerant(t *testing.T) {
+	for _, input := range []string{
+		"3146755730460562",
+		"3146 7557 3046 0562",
+		"31 467557304 60562",
+		"  3146  7557 3046 0562 ",
+	} {
+		dbuf, err := DecodeGroups(input, " ")
+		testEqual(t, "DecodeGroups(%q) = error %v, want %v", input, err, error(nil))
+		testEqual(t, "DecodeGroups(%q) = %q, want %q", input, string(dbuf), "foobar")
+	}
+
+	for _, tc := range []struct {
+		input string
+		sep   string
+		want  int64
+	}{
+		{"3146-7557-3946-0562", "-", 11},
+		{"3146--7557--3946", "--", 13},
+		{"3146 7557 3046 0", " ", 10},
+		{"3146 9557", " ", 5},
+	} {
+		_, err := DecodeGroups(tc.input, tc.sep)
+		testEqual(t, "DecodeGroups(%q, %q) reported corruption at %v, want %v", tc.input, tc.sep, corruptOffset(err), tc.want)
+	}
+}
+
+func TestEncodeToGroupsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("EncodeToGroups(%q, 0, %q) did not panic", "foo", " ")
+		}
+	}()
+	EncodeToGroups([]byte("foo"), 0, " ")
+}
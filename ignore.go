@@ -0,0 +1,133 @@
+package base8
+
+import "io"
+
+// ignoredIndex marks characters in an Encoding's decodeMap that the decoder
+// skips.
+const ignoredIndex = '\xfe'
+
+// WithIgnoredChars creates a new encoding identical to enc except that its
+// decoders skip every occurrence of the bytes in chars, such as the spaces
+// and line breaks found in base8 copied from emails, YAML blocks or wrapped
+// files. The characters must not be contained in the encoding's alphabet or
+// be its padding character. Ignored characters are accepted anywhere in the
+// input, including inside padding, and are never produced by the encoder.
+//
+// Offsets reported by the resulting encoding's CorruptInputErrors count the
+// ignored characters, so they locate the offending byte in the original
+// input. Decoding with ignored characters requires a temporary copy of the
+// input that omits them.
+func (enc Encoding) WithIgnoredChars(chars string) *Encoding {
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		switch {
+		case enc.decodeMap[c] <= 7:
+			panic("ignored character contained in alphabet")
+		case enc.padChar != NoPadding && rune(c) == enc.padChar:
+			panic("ignored character is the padding character")
+		}
+		enc.decodeMap[c] = ignoredIndex
+		enc.ignores = true
+	}
+	return &enc
+}
+
+// stripIgnored returns a copy of src without the characters that enc
+// ignores.
+func stripIgnored[T ~string | ~[]byte](enc *Encoding, src T) []byte {
+	buf := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		if enc.decodeMap[src[i]] != ignoredIndex {
+			buf = append(buf, src[i])
+		}
+	}
+	return buf
+}
+
+// unstripOffset converts an offset into stripIgnored(enc, src) into the
+// corresponding offset into src.
+func unstripOffset[T ~string | ~[]byte](enc *Encoding, src T, off int64) int64 {
+	for i := 0; i < len(src); i++ {
+		if enc.decodeMap[src[i]] == ignoredIndex {
+			continue
+		}
+		if off == 0 {
+			return int64(i)
+		}
+		off--
+	}
+	return int64(len(src))
+}
+
+// unstrip returns err, which was returned for stripIgnored(enc, src), with
+// its offset converted into an offset into src.
+func unstrip[T ~string | ~[]byte](enc *Encoding, src T, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := err.(CorruptInputError)
+	return e.shift(unstripOffset(enc, src, e.offset) - e.offset)
+}
+
+// An ignoredRun records n ignored bytes that preceded the kept byte with
+// index at.
+type ignoredRun struct {
+	at, n int64
+}
+
+// ignoringReader removes the characters ignored by enc from the stream read
+// from r, remembering where they were so that offsets into the filtered
+// stream can be mapped back to offsets into r.
+type ignoringReader struct {
+	enc  *Encoding
+	r    io.Reader
+	kept int64 // number of bytes returned so far
+	base int64 // number of ignored bytes preceding the first run
+	runs []ignoredRun
+}
+
+func (r *ignoringReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.r.Read(p)
+		k := 0
+		for _, b := range p[0:n] {
+			if r.enc.decodeMap[b] != ignoredIndex {
+				p[k] = b
+				k++
+				continue
+			}
+			at := r.kept + int64(k)
+			if l := len(r.runs); l > 0 && r.runs[l-1].at == at {
+				r.runs[l-1].n++
+			} else {
+				r.runs = append(r.runs, ignoredRun{at: at, n: 1})
+			}
+		}
+		r.kept += int64(k)
+		if k > 0 || n == 0 || err != nil {
+			return k, err
+		}
+	}
+}
+
+// offset returns the offset in r of the kept byte with index k.
+func (r *ignoringReader) offset(k int64) int64 {
+	off := k + r.base
+	for _, run := range r.runs {
+		if run.at > k {
+			break
+		}
+		off += run.n
+	}
+	return off
+}
+
+// discard forgets the positions of the ignored bytes preceding the kept byte
+// with index k, which is no longer needed by offset.
+func (r *ignoringReader) discard(k int64) {
+	i := 0
+	for ; i < len(r.runs) && r.runs[i].at <= k; i++ {
+		r.base += r.runs[i].n
+	}
+	r.runs = append(r.runs[:0], r.runs[i:]...)
+}
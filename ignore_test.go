@@ -0,0 +1,132 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// wrap inserts sep after every width bytes of s.
+func wrap(s string, width int, sep string) string {
+	var sb strings.Builder
+	for len(s) > width {
+		sb.WriteString(s[:width])
+		sb.WriteString(sep)
+		s = s[width:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+func TestWithIgnoredChars(t *testing.T) {
+	for _, base := range []*Encoding{StdEncoding, RawEncoding, LetterEncoding} {
+		enc := base.WithIgnoredChars(" \t\r\n")
+		for _, p := range append(pairs, bigtest) {
+			for _, input := range []string{
+				base.EncodeToString([]byte(p.decoded)),
+				wrap(base.EncodeToString([]byte(p.decoded)), 5, "\r\n"),
+				" " + wrap(base.EncodeToString([]byte(p.decoded)), 1, " \t") + "\n",
+			} {
+				dbuf, err := enc.DecodeString(input)
+				testEqual(t, "DecodeString(%q) = error %v, want %v", input, err, error(nil))
+				testEqual(t, "DecodeString(%q) = %q, want %q", input, string(dbuf), p.decoded)
+
+				dbuf, err = enc.AppendDecode(nil, []byte(input))
+				testEqual(t, "AppendDecode(%q) = error %v, want %v", input, err, error(nil))
+				testEqual(t, "AppendDecode(%q) = %q, want %q", input, string(dbuf), p.decoded)
+
+				n, err := enc.Validate([]byte(input))
+				testEqual(t, "Validate(%q) = error %v, want %v", input, err, error(nil))
+				testEqual(t, "Validate(%q) = %v, want %v", input, n, len(input))
+				testEqual(t, "IsValid(%q) = %v, want %v", input, enc.IsValid(input), true)
+
+				dbuf = make([]byte, enc.DecodedLen(len(input)))
+				nDst, nSrc, err := enc.DecodeConsumed(dbuf, []byte(input))
+				testEqual(t, "DecodeConsumed(%q) = error %v, want %v", input, err, error(nil))
+				testEqual(t, "DecodeConsumed(%q) = %q, want %q", input, string(dbuf[:nDst]), p.decoded)
+				if want := strings.TrimRight(input, " \t\r\n"); nSrc < len(want) {
+					t.Errorf("DecodeConsumed(%q) consumed %d bytes, want at least %d", input, nSrc, len(want))
+				}
+
+				var seq []byte
+				for b, err := range enc.DecodeSeq(input) {
+					testEqual(t, "DecodeSeq(%q) = error %v, want %v", input, err, error(nil))
+					seq = append(seq, b)
+				}
+				testEqual(t, "DecodeSeq(%q) = %q, want %q", input, string(seq), p.decoded)
+
+				for _, size := range []int{8, 1024} {
+					got, err := io.ReadAll(enc.NewDecoderSize(iotest.OneByteReader(strings.NewReader(input)), size))
+					testEqual(t, "NewDecoder(%q) = error %v, want %v", input, err, error(nil))
+					testEqual(t, "NewDecoder(%q) = %q, want %q", input, string(got), p.decoded)
+				}
+			}
+		}
+	}
+}
+
+func TestWithIgnoredCharsCorrupt(t *testing.T) {
+	enc := StdEncoding.WithIgnoredChars("\n")
+	encoded := []byte(EncodeToString(bytes.Repeat([]byte("foobar"), 300)))
+	for _, off := range []int{0, 9, 1000, 1599} {
+		corrupt := slices.Clone(encoded)
+		corrupt[off] = '9'
+		input := wrap(string(corrupt), 7, "\n")
+		want := int64(off + off/7)
+
+		_, err := enc.DecodeString(input)
+		testEqual(t, "DecodeString: corruption at %d reported at %v, want %v", off, corruptOffset(err), want)
+		n, err := enc.Validate([]byte(input))
+		testEqual(t, "Validate: corruption at %d reported at %v, want %v", off, corruptOffset(err), want)
+		testEqual(t, "Validate: corruption at %d returned %v, want %v", off, int64(n), want)
+		for b, err := range enc.DecodeSeq(input) {
+			if err != nil {
+				testEqual(t, "DecodeSeq: corruption at %d reported at %v, want %v", off, corruptOffset(err), want)
+				testEqual(t, "DecodeSeq: corruption at %d yielded %v, want %v", off, b, byte(0))
+			}
+		}
+		for _, size := range []int{8, 64, 1024} {
+			r := enc.NewDecoderSize(iotest.HalfReader(strings.NewReader(input)), size)
+			_, err = io.ReadAll(r)
+			testEqual(t, "NewDecoderSize(%d): corruption at %d reported at %v, want %v", size, off, corruptOffset(err), want)
+
+			// A reset decoder starts counting again.
+			r.(interface{ Reset(io.Reader) }).Reset(strings.NewReader(input))
+			_, err = io.ReadAll(r)
+			testEqual(t, "Reset NewDecoderSize(%d): corruption at %d reported at %v, want %v", size, off, corruptOffset(err), want)
+		}
+	}
+
+	// Truncated input is reported at the start of its final quantum.
+	_, err := enc.DecodeString("31467557\n31\n4\n")
+	testEqual(t, "DecodeString(%q) = %v, want %v", "31467557\n31\n4\n", err, error(truncated(9, ExpectPadding)))
+}
+
+func TestWithIgnoredCharsPanics(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		f    func()
+	}{
+		{"alphabet", func() { StdEncoding.WithIgnoredChars(" 0") }},
+		{"padding", func() { StdEncoding.WithIgnoredChars("=") }},
+		{"ignored padding", func() { StdEncoding.WithPadding(NoPadding).WithIgnoredChars(".").WithPadding('.') }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tc.name)
+				}
+			}()
+			tc.f()
+		}()
+	}
+
+	// Raw encodings may ignore the standard padding character.
+	enc := RawEncoding.WithIgnoredChars("=")
+	dbuf, err := enc.DecodeString("314=====")
+	testEqual(t, "DecodeString(%q) = error %v, want %v", "314=====", err, error(nil))
+	testEqual(t, "DecodeString(%q) = %q, want %q", "314=====", string(dbuf), "f")
+}
@@ -0,0 +1,58 @@
+package base8
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+var errInvalidInt = errors.New("base8: invalid encoded integer")
+
+// EncodeInt64 returns a compact base8 encoding of v in sign-magnitude form.
+// The encoded data is a sign byte, 0 for zero and positive values and 1 for
+// negative values, followed by the magnitude of v in big-endian order with no
+// leading zero bytes. Zero has an empty magnitude. Values of small magnitude
+// thus encode to short strings regardless of sign: both 1 and -1 encode to
+// 8 characters.
+func EncodeInt64(v int64) string {
+	var sign byte
+	mag := uint64(v)
+	if v < 0 {
+		sign, mag = 1, -mag
+	}
+
+	var b [9]byte
+	b[0] = sign
+	n := (bits.Len64(mag) + 7) / 8
+	for i := n; i > 0; i-- {
+		b[i] = byte(mag)
+		mag >>= 8
+	}
+	return EncodeToString(b[0 : n+1])
+}
+
+// DecodeInt64 returns the integer represented by the base8 string s, which
+// must have been produced by EncodeInt64. Non-canonical encodings, such as
+// magnitudes with leading zero bytes or a negative zero, are rejected.
+func DecodeInt64(s string) (int64, error) {
+	b, err := DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) == 0 || len(b) > 9 || b[0] > 1 || len(b) > 1 && b[1] == 0 {
+		return 0, errInvalidInt
+	}
+
+	var mag uint64
+	for _, c := range b[1:] {
+		mag = mag<<8 | uint64(c)
+	}
+	switch {
+	case b[0] == 0 && mag <= math.MaxInt64:
+		return int64(mag), nil
+	case b[0] == 1 && mag != 0 && mag <= 1<<63:
+		return -int64(mag), nil
+	default:
+		return 0, errInvalidInt
+	}
+}
@@ -0,0 +1,56 @@
+package base8
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInt64(t *testing.T) {
+	testCases := []struct {
+		v    int64
+		want string
+	}{
+		{0, "000====="},
+		{1, "000004=="},
+		{-1, "002004=="},
+		{255, "001774=="},
+		{-256, "00200400"},
+		{math.MaxInt64, ""},
+		{math.MinInt64, ""},
+		{math.MinInt64 + 1, ""},
+		{123456789, ""},
+		{-123456789, ""},
+	}
+	for _, tc := range testCases {
+		s := EncodeInt64(tc.v)
+		if tc.want != "" {
+			testEqual(t, "EncodeInt64(%d) = %q, want %q", tc.v, s, tc.want)
+		}
+		got, err := DecodeInt64(s)
+		if err != nil {
+			t.Errorf("DecodeInt64(%q) failed: %v", s, err)
+			continue
+		}
+		testEqual(t, "DecodeInt64(EncodeInt64(%d)) = %d, want %d", tc.v, got, tc.v)
+	}
+}
+
+func TestDecodeInt64Invalid(t *testing.T) {
+	for _, raw := range [][]byte{
+		{},
+		{2, 1},
+		{0, 0, 1},
+		{1},
+		{0, 0x80, 0, 0, 0, 0, 0, 0, 0},
+		{1, 0x80, 0, 0, 0, 0, 0, 0, 1},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	} {
+		s := EncodeToString(raw)
+		if _, err := DecodeInt64(s); err != errInvalidInt {
+			t.Errorf("DecodeInt64(%q) = %v, want %v", s, err, errInvalidInt)
+		}
+	}
+
+	_, err := DecodeInt64("0020020!")
+	testEqual(t, "DecodeInt64(corrupt) = %v, want %v", corruptOffset(err), int64(7))
+}
@@ -0,0 +1,48 @@
+package base8
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrLimitExceeded is returned by a decoder created by LimitDecoder when the
+// decoded data is longer than its limit.
+var ErrLimitExceeded = errors.New("base8: decoded data exceeds limit")
+
+type limitedDecoder struct {
+	err error
+	r   io.Reader
+	n   int64 // number of bytes that may still be returned
+}
+
+func (l *limitedDecoder) Read(p []byte) (n int, err error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	// Read one byte beyond the limit so that decoded data of exactly the
+	// limit's length can be told apart from longer data.
+	if int64(len(p)) > l.n {
+		p = p[0 : l.n+1]
+	}
+	n, err = l.r.Read(p)
+	if int64(n) > l.n {
+		n, err = int(l.n), ErrLimitExceeded
+	}
+	l.n -= int64(n)
+	if err != nil {
+		l.err = err
+	}
+	return n, err
+}
+
+// LimitDecoder returns a reader that reads decoded data from r, a stream
+// decoder such as one returned by NewDecoder, but fails with
+// ErrLimitExceeded once more than n decoded bytes are available. Unlike
+// io.LimitReader, which silently truncates its input, LimitDecoder reports
+// oversized input as an error, so that services decoding untrusted data can
+// bound the memory and disk it consumes. The first n bytes are returned
+// before the error.
+func LimitDecoder(r io.Reader, n int64) io.Reader {
+	return &limitedDecoder{r: r, n: max(n, 0)}
+}
@@ -0,0 +1,38 @@
+package base8
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLimitDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		limit int64
+		want  string
+		err   error
+	}{
+		{0, "", ErrLimitExceeded},
+		{3, "foo", ErrLimitExceeded},
+		{5, "fooba", ErrLimitExceeded},
+		{6, "foobar", nil},
+		{100, "foobar", nil},
+	} {
+		for _, onebyte := range []bool{false, true} {
+			var r io.Reader = LimitDecoder(NewDecoder(strings.NewReader("3146755730460562")), tc.limit)
+			if onebyte {
+				r = iotest.OneByteReader(r)
+			}
+			got, err := io.ReadAll(r)
+			testEqual(t, "LimitDecoder(%d) = error %v, want %v", tc.limit, err, tc.err)
+			testEqual(t, "LimitDecoder(%d) = %q, want %q", tc.limit, string(got), tc.want)
+		}
+	}
+
+	// Corrupt input is reported as such when it lies within the limit.
+	_, err := io.ReadAll(LimitDecoder(NewDecoder(strings.NewReader("31467x57")), 10))
+	if _, ok := err.(CorruptInputError); !ok {
+		t.Errorf("LimitDecoder of corrupt input = %v, want CorruptInputError", err)
+	}
+}
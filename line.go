@@ -0,0 +1,122 @@
+package base8
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// LineError describes corrupt input found by a decoder returned by
+// NewLineDecoder. It wraps the CorruptInputError that gives the offset of the
+// offending byte in the input stream, counting whitespace.
+type LineError struct {
+	err       CorruptInputError
+	line, col int
+}
+
+// Line returns the 1-based line number of the offending byte.
+func (e *LineError) Line() int {
+	return e.line
+}
+
+// Column returns the 1-based column number, in bytes, of the offending byte.
+func (e *LineError) Column() int {
+	return e.col
+}
+
+// Offset returns the offset of the offending byte in the input stream.
+func (e *LineError) Offset() int64 {
+	return e.err.offset
+}
+
+func (e *LineError) Error() string {
+	return "illegal base8 data at line " + strconv.Itoa(e.line) + ", column " + strconv.Itoa(e.col)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.err
+}
+
+// linePos records the position of a byte in the input stream.
+type linePos struct {
+	offset    int64
+	line, col int
+}
+
+type lineDecoder struct {
+	err    error
+	r      *bufio.Reader
+	pos    linePos // position of the next input byte
+	end    bool    // saw end of message
+	out    []byte  // leftover decoded output
+	outbuf [3]byte
+}
+
+// next reads and decodes the next quantum, skipping whitespace.
+func (d *lineDecoder) next() error {
+	if d.end {
+		return io.EOF
+	}
+
+	var q [8]byte
+	var pos [8]linePos
+	for j := 0; j < 8; {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && j > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		p := d.pos
+		d.pos.offset++
+		d.pos.col++
+		switch c {
+		case '\n':
+			d.pos.line, d.pos.col = d.pos.line+1, 1
+			continue
+		case ' ', '\t', '\r':
+			continue
+		}
+		q[j], pos[j] = c, p
+		j++
+	}
+
+	n, end, err := StdEncoding.decode(d.outbuf[0:], q[0:])
+	if err != nil {
+		e := err.(CorruptInputError)
+		p := pos[e.offset]
+		e.offset = p.offset
+		return &LineError{err: e, line: p.line, col: p.col}
+	}
+	d.out, d.end = d.outbuf[0:n], end
+	return nil
+}
+
+func (d *lineDecoder) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(d.out) > 0 {
+			nn := copy(p[n:], d.out)
+			d.out = d.out[nn:]
+			n += nn
+			continue
+		}
+		if d.err != nil {
+			break
+		}
+		d.err = d.next()
+	}
+	if n == len(p) {
+		return n, nil
+	}
+	return n, d.err
+}
+
+// NewLineDecoder constructs a new base8 stream decoder for human-edited
+// input. The decoder ignores spaces, tabs, carriage returns and newlines, and
+// reports corrupt input as a *LineError that gives the line and column of the
+// offending byte.
+func NewLineDecoder(r io.Reader) io.Reader {
+	return &lineDecoder{r: bufio.NewReader(r), pos: linePos{line: 1, col: 1}}
+}
@@ -0,0 +1,55 @@
+package base8
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLineDecoder(t *testing.T) {
+	input := "3146 7557\r\n  30460562\n\t3146755730460562\n"
+	decoded, err := ioutil.ReadAll(NewLineDecoder(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(NewLineDecoder(...)): %v", err)
+	}
+	testEqual(t, "Decoding of %q = %q, want %q", input, string(decoded), "foobarfoobar")
+
+	var wrapped strings.Builder
+	for i := 0; i < len(bigtest.encoded); i += 7 {
+		end := i + 7
+		if end > len(bigtest.encoded) {
+			end = len(bigtest.encoded)
+		}
+		wrapped.WriteString(bigtest.encoded[i:end] + "\n")
+	}
+	decoded, err = ioutil.ReadAll(NewLineDecoder(strings.NewReader(wrapped.String())))
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(NewLineDecoder(...)): %v", err)
+	}
+	testEqual(t, "Decoding of %q = %q, want %q", wrapped.String(), string(decoded), bigtest.decoded)
+}
+
+func TestLineDecoderError(t *testing.T) {
+	input := "31467557\n30460562\n3146755730x60562\n"
+	_, err := ioutil.ReadAll(NewLineDecoder(iotest.OneByteReader(strings.NewReader(input))))
+
+	var le *LineError
+	if !errors.As(err, &le) {
+		t.Fatalf("NewLineDecoder error = %T (%v), want *LineError", err, err)
+	}
+	testEqual(t, "LineError.Line() = %v, want %v", le.Line(), 3)
+	testEqual(t, "LineError.Column() = %v, want %v", le.Column(), 11)
+	testEqual(t, "LineError.Offset() = %v, want %v", le.Offset(), int64(28))
+	testEqual(t, "LineError.Error() = %q, want %q", le.Error(), "illegal base8 data at line 3, column 11")
+
+	var cie CorruptInputError
+	if !errors.As(err, &cie) || cie != (CorruptInputError{offset: 28, b: 'x', expected: ExpectSymbol, err: ErrInvalidCharacter}) {
+		t.Errorf("NewLineDecoder error = %v, want CorruptInputError(28)", err)
+	}
+
+	_, err = ioutil.ReadAll(NewLineDecoder(strings.NewReader("31467557\n3146")))
+	testEqual(t, "Truncated input error = %v, want %v", err, io.ErrUnexpectedEOF)
+}
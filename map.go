@@ -0,0 +1,34 @@
+package base8
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// EncodeMap returns a map with the same keys as m whose values are the base8
+// encodings of the corresponding values in m.
+func EncodeMap(m map[string][]byte) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = EncodeToString(v)
+	}
+	return out
+}
+
+// DecodeMap returns a map with the same keys as m whose values are the bytes
+// represented by the corresponding base8 strings in m. If any value is
+// invalid, DecodeMap returns an error that names the offending key and wraps
+// the underlying CorruptInputError. If several values are invalid, the error
+// names the first such key in sorted order.
+func DecodeMap(m map[string]string) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(m))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		v, err := DecodeString(m[k])
+		if err != nil {
+			return nil, fmt.Errorf("base8: decoding value for key %q: %w", k, err)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package base8
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeMap(t *testing.T) {
+	m := map[string][]byte{
+		"empty": {},
+		"f":     []byte("f"),
+		"foo":   []byte("foo"),
+		"bin":   {0, 1, 2, 0xff},
+		"big":   []byte(bigtest.decoded),
+	}
+	encoded := EncodeMap(m)
+	testEqual(t, "len(EncodeMap(m)) = %v, want %v", len(encoded), len(m))
+	testEqual(t, "EncodeMap(m)[%q] = %q, want %q", "big", encoded["big"], bigtest.encoded)
+
+	decoded, err := DecodeMap(encoded)
+	if err != nil {
+		t.Fatalf("DecodeMap failed: %v", err)
+	}
+	testEqual(t, "len(DecodeMap(EncodeMap(m))) = %v, want %v", len(decoded), len(m))
+	for k, v := range m {
+		if got, ok := decoded[k]; !ok || !bytes.Equal(got, v) {
+			t.Errorf("DecodeMap(EncodeMap(m))[%q] = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestDecodeMapCorrupt(t *testing.T) {
+	_, err := DecodeMap(map[string]string{
+		"good": "31467557",
+		"bad":  "3146755!",
+	})
+	if err == nil || !strings.Contains(err.Error(), `"bad"`) {
+		t.Errorf("DecodeMap error = %v, want mention of key %q", err, "bad")
+	}
+	var cie CorruptInputError
+	if !errors.As(err, &cie) || cie.Offset() != 7 {
+		t.Errorf("DecodeMap error = %v, want CorruptInputError(7)", err)
+	}
+}
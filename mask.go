@@ -0,0 +1,22 @@
+package base8
+
+// EncodeMasked returns the base8 encoding of src with each byte XORed with
+// mask. This is casual obfuscation only: it hides data from a quick glance,
+// not from anyone trying to recover it, and must not be used for security.
+func EncodeMasked(src []byte, mask byte) string {
+	masked := make([]byte, len(src))
+	for i, b := range src {
+		masked[i] = b ^ mask
+	}
+	return EncodeToString(masked)
+}
+
+// DecodeMasked returns the bytes represented by the base8 string s with each
+// byte XORed with mask, reversing EncodeMasked.
+func DecodeMasked(s string, mask byte) ([]byte, error) {
+	b, err := DecodeString(s)
+	for i := range b {
+		b[i] ^= mask
+	}
+	return b, err
+}
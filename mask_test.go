@@ -0,0 +1,25 @@
+package base8
+
+import "testing"
+
+func TestMasked(t *testing.T) {
+	for _, mask := range []byte{0, 0xff, 0x5a, 1} {
+		for _, p := range append(pairs, bigtest) {
+			encoded := EncodeMasked([]byte(p.decoded), mask)
+			if mask == 0 {
+				testEqual(t, "EncodeMasked(%q, 0) = %q, want %q", p.decoded, encoded, p.encoded)
+			} else if p.decoded != "" && encoded == p.encoded {
+				t.Errorf("EncodeMasked(%q, %#x) = %q, want masked output", p.decoded, mask, encoded)
+			}
+
+			decoded, err := DecodeMasked(encoded, mask)
+			if err != nil {
+				t.Errorf("DecodeMasked(%q, %#x) failed: %v", encoded, mask, err)
+			}
+			testEqual(t, "DecodeMasked(%q, %#x) = %q, want %q", encoded, mask, string(decoded), p.decoded)
+		}
+	}
+
+	got := EncodeMasked([]byte{0x00}, 0xff)
+	testEqual(t, "EncodeMasked(%q, 0xff) = %q, want %q", "\x00", got, "776=====")
+}
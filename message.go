@@ -0,0 +1,142 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+)
+
+type messageDecoder struct {
+	err    error // error from r
+	derr   error // corrupt input error
+	enc    *Encoding
+	r      io.Reader
+	end    bool   // reached the end of the current message
+	buf    []byte // input not yet decoded
+	nbuf   int
+	offset int64           // offset of buf[0] in the input stream
+	ign    *ignoringReader // filters r if enc ignores characters
+	out    []byte          // leftover decoded output
+	outbuf []byte
+}
+
+// fill decodes the next chunk of the current message into d.out. A chunk
+// ends early with a quantum that holds padding, which ends the message.
+func (d *messageDecoder) fill() error {
+	if d.derr != nil {
+		return d.derr
+	}
+	for d.nbuf < 8 && d.err == nil {
+		var nn int
+		nn, d.err = d.r.Read(d.buf[d.nbuf:])
+		d.nbuf += nn
+	}
+
+	nr := d.nbuf
+	if d.nbuf < 8 {
+		if d.err != io.EOF {
+			return d.err
+		}
+		if d.nbuf == 0 {
+			// The end of the input ends the last message.
+			d.end = true
+			return nil
+		}
+		// Decode the incomplete final quantum to report why it is invalid.
+	} else {
+		nr = d.nbuf / 8 * 8
+		for i := 0; i < nr; i += 8 {
+			if bytes.IndexByte(d.buf[i:i+8], byte(d.enc.padChar)) >= 0 {
+				nr = i + 8
+				break
+			}
+		}
+	}
+
+	nw, end, err := decodeQuanta(d.enc, d.outbuf[0:], d.buf[0:nr])
+	d.out = d.outbuf[0:nw]
+	d.end = end
+	if err != nil {
+		// Report the offset from the start of the stream. The bytes decoded
+		// before the corruption are returned first.
+		e := err.(CorruptInputError)
+		off := d.offset + e.offset
+		if d.ign != nil {
+			off = d.ign.offset(off)
+		}
+		d.derr = e.shift(off - e.offset)
+	}
+	d.nbuf = copy(d.buf[0:], d.buf[nr:d.nbuf])
+	d.offset += int64(nr)
+	if d.ign != nil {
+		d.ign.discard(d.offset)
+	}
+	return nil
+}
+
+func (d *messageDecoder) Read(p []byte) (n int, err error) {
+	for len(d.out) == 0 {
+		if d.end {
+			return 0, io.EOF
+		}
+		if err := d.fill(); err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// NextMessage discards whatever remains of the current message and advances
+// to the next one. It returns io.EOF if no input follows the current message.
+func (d *messageDecoder) NextMessage() error {
+	d.out = nil
+	for !d.end {
+		if err := d.fill(); err != nil {
+			return err
+		}
+		d.out = nil
+	}
+
+	for d.nbuf == 0 && d.err == nil {
+		d.nbuf, d.err = d.r.Read(d.buf[0:])
+	}
+	if d.nbuf == 0 {
+		return d.err
+	}
+	d.end = false
+	return nil
+}
+
+// NewMessageDecoder constructs a new base8 stream decoder for a sequence of
+// separately padded messages, such as the output of an encoder that was
+// flushed after each message. Read returns io.EOF at the end of each message,
+// which is the quantum that holds its padding or, for the last message, the
+// end of the input. The returned decoder also has a NextMessage() error
+// method that skips any unread data of the current message and advances to
+// the next, returning io.EOF once there are no more messages.
+//
+// Messages are delimited only by their padding, so a message whose length is
+// a multiple of 3, which needs no padding, runs into the message that follows
+// it. enc must use padding and must not have a length escape. The offset of a CorruptInputError it returns is
+// counted from the start of the stream.
+func (enc *Encoding) NewMessageDecoder(r io.Reader) io.Reader {
+	if enc.padChar == NoPadding {
+		panic("message decoding requires padding")
+	}
+	if enc.escape {
+		panic("message decoding with a length escape")
+	}
+	d := &messageDecoder{enc: enc, r: r, buf: make([]byte, defaultBufSize), outbuf: make([]byte, defaultBufSize/8*3)}
+	if enc.ignores {
+		d.ign = &ignoringReader{enc: enc, r: r}
+		d.r = d.ign
+	}
+	return d
+}
+
+// NewMessageDecoder constructs a new base8 stream decoder for a sequence of
+// separately padded messages that use the standard encoding.
+func NewMessageDecoder(r io.Reader) io.Reader {
+	return StdEncoding.NewMessageDecoder(r)
+}
@@ -0,0 +1,100 @@
+package base8
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type messageReader interface {
+	io.Reader
+	NextMessage() error
+}
+
+// readMessages reads every message from d.
+func readMessages(d messageReader) ([]string, error) {
+	var msgs []string
+	for {
+		msg, err := io.ReadAll(d)
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, string(msg))
+		if err := d.NextMessage(); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return msgs, err
+		}
+	}
+}
+
+func TestMessageDecoder(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{""}},
+		{"314=====", []string{"f"}},
+		{"314=====314674==31467557304=====", []string{"f", "fo", "foob"}},
+		{"31467557314=====", []string{"foof"}},
+		{"314=====31467557", []string{"f", "foo"}},
+	} {
+		for _, size := range []int{0, 1, 8} {
+			r := io.Reader(strings.NewReader(tc.input))
+			if size == 1 {
+				r = iotest.OneByteReader(r)
+			}
+			got, err := readMessages(NewMessageDecoder(r).(messageReader))
+			if err != nil || strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("NewMessageDecoder(%q) = %q, %v, want %q", tc.input, got, err, tc.want)
+			}
+		}
+	}
+
+	// Many messages spanning several buffers.
+	var input strings.Builder
+	var want []string
+	for i := 0; i < 500; i++ {
+		msg := strings.Repeat("x", i%7+1)
+		if len(msg)%3 == 0 {
+			msg += "y"
+		}
+		input.WriteString(EncodeToString([]byte(msg)))
+		want = append(want, msg)
+	}
+	d := StdEncoding.WithIgnoredChars("\n").NewMessageDecoder(strings.NewReader(strings.ReplaceAll(input.String(), "=", "=\n")))
+	got, err := readMessages(d.(messageReader))
+	if err != nil || strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("NewMessageDecoder of %d messages = %d messages, %v", len(want), len(got), err)
+	}
+
+	// NextMessage skips the unread part of a message.
+	d = NewMessageDecoder(strings.NewReader("3146755730460562314=====314674=="))
+	p := make([]byte, 2)
+	io.ReadFull(d, p)
+	if err := d.(messageReader).NextMessage(); err != nil {
+		t.Fatalf("NextMessage() = %v, want nil", err)
+	}
+	rest, err := io.ReadAll(d)
+	testEqual(t, "message after NextMessage = error %v, want %v", err, error(nil))
+	testEqual(t, "message after NextMessage = %q, want %q", string(rest), "fo")
+	testEqual(t, "final NextMessage() = %v, want %v", d.(messageReader).NextMessage(), io.EOF)
+}
+
+func TestMessageDecoderCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		offset int64
+	}{
+		{"314=====31x67557", 10},
+		{"314=====314", 8},
+		{"314=====31=67557", 10},
+	} {
+		got, err := readMessages(NewMessageDecoder(strings.NewReader(tc.input)).(messageReader))
+		if corruptOffset(err) != tc.offset {
+			t.Errorf("NewMessageDecoder(%q) = %q, %v, want offset %d", tc.input, got, err, tc.offset)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package base8
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minParallelShard is the smallest number of source bytes that
+// EncodeParallel and DecodeParallel hand to a worker goroutine. Smaller shards cost more to
+// schedule than they save. It is a multiple of 3 and of 8 so that shards of
+// either encoded or decoded data hold only whole quanta.
+const minParallelShard = 96 << 10
+
+// shards splits n items, processed in groups of quantum, into at most
+// GOMAXPROCS shards of at least minParallelShard items each and calls f with
+// each shard's bounds concurrently. It returns once every call has returned.
+func shards(n, quantum int, f func(lo, hi int)) {
+	workers := min(runtime.GOMAXPROCS(0), n/minParallelShard)
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+
+	size := (n/workers + quantum - 1) / quantum * quantum
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += size {
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			f(lo, hi)
+		}(lo, min(lo+size, n))
+	}
+	wg.Wait()
+}
+
+// EncodeParallel is like Encode, but splits large inputs into shards that it
+// encodes concurrently on up to GOMAXPROCS goroutines. Every 3 bytes of src
+// encode to 8 bytes of dst independently of the rest, so the shards write to
+// disjoint regions of dst and the output is identical to that of Encode.
+// Inputs too small to benefit are encoded on the calling goroutine.
+func (enc *Encoding) EncodeParallel(dst, src []byte) {
+	shards(len(src), 3, func(lo, hi int) {
+		enc.Encode(dst[lo/3*8:], src[lo:hi])
+	})
+}
+
+// EncodeParallel is like Encode, but encodes large inputs concurrently using
+// the standard encoding.
+func EncodeParallel(dst, src []byte) {
+	StdEncoding.EncodeParallel(dst, src)
+}
+
+// decodeAt decodes src, which starts at offset off of the encoded input, into
+// dst. If final is false, src is followed by more quanta, so it must consist
+// entirely of symbols. Errors are reported exactly as Decode would report
+// them for the whole input.
+func decodeAt(enc *Encoding, dst, src []byte, off int64, final bool) (int, error) {
+	var n int
+	var end bool
+	var err error
+	if final {
+		n, end, err = decodeStripped(enc, dst, src)
+	} else {
+		n, end, err = decodeQuanta(enc, dst, src)
+	}
+	if !final && (err != nil || end) {
+		// Every quantum is followed by another quantum, so the first byte
+		// that is not a symbol is where Decode would fail.
+		for i, c := range src {
+			if enc.decodeMap[c] > 7 {
+				return i / 8 * 3, enc.invalidSymbol(i, c).shift(off)
+			}
+		}
+	}
+	if err != nil {
+		return n, err.(CorruptInputError).shift(off)
+	}
+	return n, nil
+}
+
+// DecodeParallel is like Decode, but splits large inputs on quantum
+// boundaries into shards that it decodes concurrently on up to GOMAXPROCS
+// goroutines. The result, including the number of bytes written and any
+// CorruptInputError, is identical to that of Decode: if several shards are
+// corrupt, the error with the lowest offset is returned. Inputs too small to
+// benefit, and all input to an encoding that ignores characters or has a
+// length escape, are decoded on the calling goroutine.
+func (enc *Encoding) DecodeParallel(dst, src []byte) (n int, err error) {
+	if enc.ignores || enc.escape {
+		return enc.Decode(dst, src)
+	}
+
+	var mu sync.Mutex
+	total := 0        // bytes decoded by shards that succeeded
+	errAt := len(src) // offset of the first shard that failed
+	shards(len(src), 8, func(lo, hi int) {
+		nn, serr := decodeAt(enc, dst[lo/8*3:], src[lo:hi], int64(lo), hi == len(src))
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case serr == nil:
+			total += nn
+		case lo < errAt:
+			// All shards before this one decode completely.
+			errAt, err = lo, serr
+			n = lo/8*3 + nn
+		}
+	})
+	if err != nil {
+		return n, err
+	}
+	return total, nil
+}
+
+// DecodeParallel is like Decode, but decodes large inputs concurrently using
+// the standard encoding.
+func DecodeParallel(dst, src []byte) (n int, err error) {
+	return StdEncoding.DecodeParallel(dst, src)
+}
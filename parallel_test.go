@@ -0,0 +1,102 @@
+package base8
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+func TestEncodeParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	src := make([]byte, 4*minParallelShard+2)
+	rand.New(rand.NewSource(1)).Read(src)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, StdEncoding.WithBitOrder(LSBFirst)} {
+		for _, n := range []int{0, 1, 2, 3, minParallelShard - 1, 2*minParallelShard + 1, len(src)} {
+			want := make([]byte, enc.EncodedLen(n))
+			enc.Encode(want, src[0:n])
+			got := make([]byte, enc.EncodedLen(n))
+			enc.EncodeParallel(got, src[0:n])
+			if !bytes.Equal(got, want) {
+				t.Errorf("EncodeParallel of %d bytes differs from Encode", n)
+			}
+		}
+	}
+}
+
+func BenchmarkEncodeParallel(b *testing.B) {
+	data := make([]byte, 64<<20)
+	buf := make([]byte, EncodedLen(len(data)))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		EncodeParallel(buf, data)
+	}
+}
+
+func TestDecodeParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	src := make([]byte, 4*minParallelShard+2)
+	rand.New(rand.NewSource(1)).Read(src)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, StdEncoding.WithIgnoredChars("\n")} {
+		for _, m := range []int{0, 1, 2, 3, minParallelShard/8*3 - 1, minParallelShard + 1, len(src)} {
+			in := []byte(enc.EncodeToString(src[0:m]))
+			for _, corrupt := range []int{-1, 0, minParallelShard + 5, len(in) - 1} {
+				in := bytes.Clone(in)
+				if corrupt >= 0 && corrupt < len(in) {
+					in[corrupt] = 'x'
+				}
+
+				want := make([]byte, enc.DecodedLen(len(in)))
+				wn, werr := enc.Decode(want, in)
+				got := make([]byte, enc.DecodedLen(len(in)))
+				gn, gerr := enc.DecodeParallel(got, in)
+				if gn != wn || gerr != werr || !bytes.Equal(got[0:gn], want[0:wn]) {
+					t.Errorf("DecodeParallel of %d bytes corrupt at %d = %d, %v, want %d, %v", len(in), corrupt, gn, gerr, wn, werr)
+				}
+			}
+		}
+	}
+
+	// Padding that ends a shard other than the last is reported where Decode
+	// reports it.
+	in := []byte(EncodeToString(src))
+	end := (len(in)/4 + 7) / 8 * 8
+	for _, off := range []int{minParallelShard + 3, end - 5, end - 2} {
+		in := bytes.Clone(in)
+		copy(in[off:end], "=====")
+		for _, decode := range []func([]byte, []byte) (int, error){Decode, DecodeParallel} {
+			_, err := decode(make([]byte, DecodedLen(len(in))), in)
+			testEqual(t, "Decode with padding at %d gave offset %d, want %d", off, corruptOffset(err), int64(off))
+		}
+	}
+}
+
+func TestDecodeParallelShardBoundaries(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	// Shards of a multiple of 32 symbols end on a vector block, so any
+	// write past a shard's output would land in the next shard's.
+	src := make([]byte, 524288/8*3)
+	rand.New(rand.NewSource(1556)).Read(src)
+	for _, enc := range []*Encoding{StdEncoding, StdEncoding.WithBitOrder(LSBFirst)} {
+		in := []byte(enc.EncodeToString(src))
+		for i := 0; i < 20; i++ {
+			got := make([]byte, enc.DecodedLen(len(in)))
+			n, err := enc.DecodeParallel(got, in)
+			if err != nil || !bytes.Equal(got[0:n], src) {
+				t.Fatalf("DecodeParallel of %d bytes = %d, %v, output differs from input", len(in), n, err)
+			}
+		}
+	}
+}
+
+func BenchmarkDecodeParallel(b *testing.B) {
+	data := []byte(EncodeToString(make([]byte, 64<<20)))
+	buf := make([]byte, DecodedLen(len(data)))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		DecodeParallel(buf, data)
+	}
+}
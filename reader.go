@@ -0,0 +1,61 @@
+package base8
+
+import "io"
+
+type encodingReader struct {
+	err    error
+	enc    *Encoding
+	r      io.Reader
+	buf    []byte // raw data waiting to be encoded
+	nbuf   int    // number of bytes in buf
+	out    []byte // leftover encoded output
+	outbuf []byte
+}
+
+func (e *encodingReader) Read(p []byte) (n int, err error) {
+	for len(e.out) == 0 {
+		if e.err != nil {
+			return 0, e.err
+		}
+
+		var nr int
+		nr, e.err = e.r.Read(e.buf[e.nbuf:])
+		e.nbuf += nr
+
+		// Encode whole blocks, and at EOF the final partial block as well.
+		nn := e.nbuf - e.nbuf%3
+		if e.err == io.EOF {
+			nn = e.nbuf
+		}
+		encode(e.enc, e.outbuf[0:], e.buf[0:nn])
+		e.out = e.outbuf[0:e.enc.EncodedLen(nn)]
+		e.nbuf = copy(e.buf[0:], e.buf[nn:e.nbuf])
+	}
+
+	n = copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+// NewEncodingReader returns a reader that produces the base8 encoding of the
+// data read from r using enc. Unlike NewEncoder, which pushes encoded data
+// into a writer, the returned reader encodes only as fast as it is read, so it
+// can be handed to an API that consumes an io.Reader, such as an HTTP request
+// or response body, without an io.Pipe and goroutine. The final partial block
+// is encoded, and padded if enc uses padding, once r returns io.EOF. Any
+// other error from r is returned after the data read before it has been
+// encoded.
+func (enc *Encoding) NewEncodingReader(r io.Reader) io.Reader {
+	return &encodingReader{
+		enc:    enc,
+		r:      r,
+		buf:    make([]byte, defaultBufSize/8*3),
+		outbuf: make([]byte, defaultBufSize),
+	}
+}
+
+// NewEncodingReader returns a reader that produces the standard base8
+// encoding of the data read from r.
+func NewEncodingReader(r io.Reader) io.Reader {
+	return StdEncoding.NewEncodingReader(r)
+}
@@ -0,0 +1,45 @@
+package base8
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEncodingReader(t *testing.T) {
+	readers := map[string]func(io.Reader) io.Reader{
+		"plain":   func(r io.Reader) io.Reader { return r },
+		"onebyte": iotest.OneByteReader,
+		"half":    iotest.HalfReader,
+		"dataerr": iotest.DataErrReader,
+	}
+	big := bytes.Repeat([]byte(bigtest.decoded), 100)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for _, p := range append(pairs, testpair{string(big), StdEncoding.EncodeToString(big)}) {
+			want := enc.EncodeToString([]byte(p.decoded))
+			for name, wrap := range readers {
+				got, err := io.ReadAll(enc.NewEncodingReader(wrap(strings.NewReader(p.decoded))))
+				testEqual(t, "NewEncodingReader(%s) = error %v, want %v", name, err, error(nil))
+				testEqual(t, "NewEncodingReader(%s) = %q, want %q", name, string(got), want)
+
+				// Reading through a small buffer must give the same output.
+				got, err = io.ReadAll(iotest.OneByteReader(enc.NewEncodingReader(wrap(strings.NewReader(p.decoded)))))
+				testEqual(t, "NewEncodingReader(%s) read bytewise = error %v, want %v", name, err, error(nil))
+				testEqual(t, "NewEncodingReader(%s) read bytewise = %q, want %q", name, string(got), want)
+			}
+		}
+	}
+}
+
+func TestEncodingReaderError(t *testing.T) {
+	want := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("foob"), iotest.ErrReader(want))
+	got, err := io.ReadAll(NewEncodingReader(r))
+	testEqual(t, "NewEncodingReader error = %v, want %v", err, want)
+
+	// Only the whole block read before the error is encoded.
+	testEqual(t, "NewEncodingReader output = %q, want %q", string(got), "31467557")
+}
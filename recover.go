@@ -0,0 +1,54 @@
+package base8
+
+// A CorruptRange describes a quantum of corrupt input that DecodeRecover
+// skipped.
+type CorruptRange struct {
+	// Start and End are the offsets in the input of the first byte of the
+	// quantum and of the byte just past it.
+	Start, End int64
+
+	// Err describes the first problem found in the quantum.
+	Err CorruptInputError
+}
+
+// DecodeRecover is like Decode, but rather than stopping at corrupt input, it
+// skips the quantum that holds the corruption, resumes decoding with the next
+// quantum, and reports each quantum it skipped. This salvages what it can
+// from damaged data, such as a partially overwritten dump, at the cost of
+// silently dropping 3 decoded bytes per skipped quantum: dst receives only
+// the data decoded from valid quanta, and n is its length. Quanta are
+// counted in 8-byte steps from the start of src, so an insertion or deletion
+// makes every later quantum corrupt. The ranges are in increasing order.
+func (enc *Encoding) DecodeRecover(dst, src []byte) (n int, corrupt []CorruptRange) {
+	in := src
+	if enc.ignores {
+		in = stripIgnored(enc, src)
+	}
+
+	for i := 0; i < len(in); {
+		nn, err := decodeAt(enc, dst[n:], in[i:], int64(i), true)
+		n += nn
+		if err == nil {
+			break
+		}
+
+		e := err.(CorruptInputError)
+		start := int(e.offset) / 8 * 8
+		end := min(start+8, len(in))
+		r := CorruptRange{Start: int64(start), End: int64(end), Err: e}
+		if enc.ignores {
+			r.Start = unstripOffset(enc, src, r.Start)
+			r.End = unstripOffset(enc, src, r.End-1) + 1
+			r.Err = unstrip(enc, src, e).(CorruptInputError)
+		}
+		corrupt = append(corrupt, r)
+		i = end
+	}
+	return n, corrupt
+}
+
+// DecodeRecover is like Decode for the standard encoding, but skips and
+// reports corrupt quanta rather than stopping at the first.
+func DecodeRecover(dst, src []byte) (n int, corrupt []CorruptRange) {
+	return StdEncoding.DecodeRecover(dst, src)
+}
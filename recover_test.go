@@ -0,0 +1,38 @@
+package base8
+
+import "testing"
+
+func TestDecodeRecover(t *testing.T) {
+	type rng struct{ start, end, offset int64 }
+	for _, tc := range []struct {
+		enc     *Encoding
+		input   string
+		want    string
+		corrupt []rng
+	}{
+		{StdEncoding, "3146755730460562", "foobar", nil},
+		{StdEncoding, "31467x5730460562", "bar", []rng{{0, 8, 5}}},
+		{StdEncoding, "31467x57304x0562314=====", "f", []rng{{0, 8, 5}, {8, 16, 11}}},
+		{StdEncoding, "314=====30460562", "bar", []rng{{0, 8, 3}}},
+		{StdEncoding, "31467557304", "foo", []rng{{8, 11, 8}}},
+		{StdEncoding, "3146755730460562x", "foobar", []rng{{16, 17, 16}}},
+		{RawEncoding, "3146755x314", "f", []rng{{0, 8, 7}}},
+		{StdEncoding.WithIgnoredChars("\n"), "3146\n7x57\n3046\n0562\n", "bar", []rng{{0, 9, 6}}},
+		{StdEncoding.WithLengthEscape(), "302611433106254631664151=1324", "abcdefghij", nil},
+		{StdEncoding.WithLengthEscape(), "31467x57=1304", "b", []rng{{0, 8, 5}}},
+		{StdEncoding.WithLengthEscape(), "31467557=1x04", "foo", []rng{{8, 13, 10}}},
+		{StdEncoding.WithLengthEscape(), "=2314674", "fo", nil},
+	} {
+		dst := make([]byte, tc.enc.DecodedLen(len(tc.input)))
+		n, corrupt := tc.enc.DecodeRecover(dst, []byte(tc.input))
+		testEqual(t, "DecodeRecover(%q) = %q, want %q", tc.input, string(dst[0:n]), tc.want)
+		if len(corrupt) != len(tc.corrupt) {
+			t.Errorf("DecodeRecover(%q) reported %v, want %v", tc.input, corrupt, tc.corrupt)
+			continue
+		}
+		for i, r := range corrupt {
+			got := rng{r.Start, r.End, r.Err.Offset()}
+			testEqual(t, "DecodeRecover(%q) range = %v, want %v", tc.input, got, tc.corrupt[i])
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package base8
+
+import (
+	"bufio"
+	"io"
+)
+
+/*
+ * Run-length encoding
+ *
+ * The run-length-aware stream format is standard base8 with one additional
+ * token that may appear at any quantum boundary:
+ *
+ *	stream  = *( quantum / repeat ) [ final ]
+ *	quantum = 8digit
+ *	repeat  = PadChar 8digit
+ *	final   = 3digit 5PadChar / 6digit 2PadChar
+ *
+ * A repeat token instructs the decoder to emit the most recently decoded
+ * quantum N more times, where N is the 24-bit big-endian value represented by
+ * the token's 8 digits. N must be at least 1, and a repeat token must follow a
+ * complete quantum or another repeat token.
+ *
+ * The format is unambiguous: standard padding never appears in the first two
+ * positions of a quantum, so a PadChar at a quantum boundary can only begin a
+ * repeat token.
+ */
+
+const (
+	// rleMinRun is the smallest number of repeats that is emitted as a repeat
+	// token. A single repeat is cheaper to emit as a plain quantum.
+	rleMinRun = 2
+
+	// rleMaxRun is the largest number of repeats that fits in a single repeat
+	// token.
+	rleMaxRun = 1<<24 - 1
+)
+
+type rleEncoder struct {
+	err     error
+	w       io.Writer
+	buf     [3]byte // buffered data waiting to be encoded
+	nbuf    int     // number of bytes in buf
+	last    [3]byte // most recently emitted quantum
+	hasLast bool    // true if last is valid
+	run     int     // number of pending repeats of last
+	out     [1024]byte
+	nout    int // number of bytes in out
+}
+
+// emit appends an encoded token to the output buffer, flushing the buffer to
+// the underlying writer if necessary.
+func (e *rleEncoder) emit(token []byte) {
+	if e.nout+len(token) > len(e.out) {
+		e.flush()
+	}
+	e.nout += copy(e.out[e.nout:], token)
+}
+
+// flush writes the output buffer to the underlying writer.
+func (e *rleEncoder) flush() {
+	if e.err == nil && e.nout > 0 {
+		_, e.err = e.w.Write(e.out[0:e.nout])
+	}
+	e.nout = 0
+}
+
+// flushRun emits any pending repeats of the last quantum.
+func (e *rleEncoder) flushRun() {
+	var token [9]byte
+	if e.run < rleMinRun {
+		Encode(token[0:], e.last[0:])
+		for ; e.run > 0; e.run-- {
+			e.emit(token[0:8])
+		}
+		return
+	}
+	count := [3]byte{byte(e.run >> 16), byte(e.run >> 8), byte(e.run)}
+	token[0] = PadChar
+	Encode(token[1:], count[0:])
+	e.emit(token[0:9])
+	e.run = 0
+}
+
+// group encodes a single complete 3-byte group.
+func (e *rleEncoder) group(g []byte) {
+	if e.hasLast && g[0] == e.last[0] && g[1] == e.last[1] && g[2] == e.last[2] {
+		e.run++
+		if e.run == rleMaxRun {
+			e.flushRun()
+		}
+		return
+	}
+	e.flushRun()
+
+	var token [8]byte
+	Encode(token[0:], g)
+	e.emit(token[0:])
+	copy(e.last[0:], g)
+	e.hasLast = true
+}
+
+func (e *rleEncoder) Write(p []byte) (n int, err error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	// Leading fringe.
+	if e.nbuf > 0 {
+		var i int
+		for i = 0; i < len(p) && e.nbuf < 3; i++ {
+			e.buf[e.nbuf] = p[i]
+			e.nbuf++
+		}
+		n += i
+		p = p[i:]
+		if e.nbuf < 3 {
+			return
+		}
+		e.group(e.buf[0:])
+		e.nbuf = 0
+	}
+
+	// Complete groups.
+	for len(p) >= 3 && e.err == nil {
+		e.group(p[0:3])
+		n += 3
+		p = p[3:]
+	}
+	if e.err != nil {
+		return n, e.err
+	}
+
+	// Trailing fringe.
+	for i := 0; i < len(p); i++ {
+		e.buf[i] = p[i]
+	}
+	e.nbuf = len(p)
+	n += len(p)
+	return
+}
+
+// Close flushes any pending output from the encoder.
+// It is an error to call Write after calling Close.
+func (e *rleEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.flushRun()
+	if e.nbuf > 0 {
+		var token [8]byte
+		Encode(token[0:], e.buf[0:e.nbuf])
+		e.emit(token[0:])
+		e.nbuf = 0
+	}
+	e.flush()
+	return e.err
+}
+
+// NewRLEEncoder returns a new run-length-aware base8 stream encoder. Data
+// written to the returned writer is encoded as base8, except that runs of
+// identical 3-byte groups are collapsed into compact repeat tokens. The output
+// must be decoded using NewRLEDecoder. As with NewEncoder, the caller must
+// Close the returned encoder to flush any partially written blocks.
+func NewRLEEncoder(w io.Writer) io.WriteCloser {
+	return &rleEncoder{w: w}
+}
+
+type rleDecoder struct {
+	err     error
+	r       *bufio.Reader
+	offset  int64   // offset of the next token in the input stream
+	end     bool    // saw end of message
+	last    [3]byte // most recently decoded quantum
+	hasLast bool    // true if last is valid
+	run     int     // number of pending repeats of last
+	out     []byte  // leftover decoded output
+	outbuf  [1024 / 8 * 3]byte
+}
+
+// next decodes the next token from the input stream into d.out.
+func (d *rleDecoder) next() error {
+	if d.run > 0 {
+		n := 0
+		for ; d.run > 0 && n+3 <= len(d.outbuf); d.run-- {
+			n += copy(d.outbuf[n:], d.last[0:])
+		}
+		d.out = d.outbuf[0:n]
+		return nil
+	}
+
+	if d.end {
+		return io.EOF
+	}
+
+	var token [9]byte
+	if _, err := io.ReadFull(d.r, token[0:1]); err != nil {
+		return err
+	}
+
+	if token[0] == PadChar {
+		if !d.hasLast {
+			return StdEncoding.invalidSymbol(0, PadChar).shift(d.offset)
+		}
+		if _, err := io.ReadFull(d.r, token[1:9]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		var count [3]byte
+		n, end, err := StdEncoding.decode(count[0:], token[1:9])
+		if err != nil {
+			return err.(CorruptInputError).shift(d.offset + 1)
+		}
+		if end || n != 3 {
+			return StdEncoding.invalidSymbol(1, PadChar).shift(d.offset)
+		}
+		d.run = int(count[0])<<16 | int(count[1])<<8 | int(count[2])
+		if d.run == 0 {
+			return StdEncoding.invalidSymbol(1, token[1]).shift(d.offset)
+		}
+		d.offset += 9
+		return nil
+	}
+
+	if _, err := io.ReadFull(d.r, token[1:8]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	n, end, err := StdEncoding.decode(d.outbuf[0:], token[0:8])
+	if err != nil {
+		return err.(CorruptInputError).shift(d.offset)
+	}
+	d.out = d.outbuf[0:n]
+	d.end = end
+	d.hasLast = !end
+	copy(d.last[0:], d.out)
+	d.offset += 8
+	return nil
+}
+
+func (d *rleDecoder) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(d.out) > 0 {
+			nn := copy(p[n:], d.out)
+			d.out = d.out[nn:]
+			n += nn
+			continue
+		}
+		if d.err != nil {
+			break
+		}
+		d.err = d.next()
+	}
+	if n == len(p) {
+		return n, nil
+	}
+	return n, d.err
+}
+
+// NewRLEDecoder constructs a new stream decoder for the output of
+// NewRLEEncoder.
+func NewRLEDecoder(r io.Reader) io.Reader {
+	return &rleDecoder{r: bufio.NewReader(r)}
+}
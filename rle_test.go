@@ -0,0 +1,119 @@
+package base8
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func rleRoundTrip(t *testing.T, raw []byte) string {
+	t.Helper()
+	encoded := new(bytes.Buffer)
+	w := NewRLEEncoder(encoded)
+	nn, err := w.Write(raw)
+	if nn != len(raw) || err != nil {
+		t.Fatalf("RLEEncoder.Write(raw) = %d, %v want %d, nil", nn, err, len(raw))
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("RLEEncoder.Close() = %v want nil", err)
+	}
+	result := encoded.String()
+
+	decoded, err := ioutil.ReadAll(NewRLEDecoder(encoded))
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll(NewRLEDecoder(...)): %v", err)
+	}
+	if !bytes.Equal(raw, decoded) {
+		t.Fatalf("RLEDecode(RLEEncode(%d-byte input)) = %d bytes, mismatch", len(raw), len(decoded))
+	}
+	return result
+}
+
+func TestRLEPairs(t *testing.T) {
+	// Inputs without runs must produce standard base8.
+	for _, p := range pairs {
+		got := rleRoundTrip(t, []byte(p.decoded))
+		testEqual(t, "RLEEncode(%q) = %q, want %q", p.decoded, got, p.encoded)
+	}
+	got := rleRoundTrip(t, []byte(bigtest.decoded))
+	testEqual(t, "RLEEncode(%q) = %q, want %q", bigtest.decoded, got, bigtest.encoded)
+}
+
+func TestRLETokens(t *testing.T) {
+	testCases := []struct {
+		decoded, encoded string
+	}{
+		{"\x00\x00\x00\x00\x00\x00", "0000000000000000"},
+		{"\x00\x00\x00\x00\x00\x00\x00\x00\x00", "00000000=00000002"},
+		{"\x00\x00\x00\x00\x00\x00\x00\x00\x00f", "00000000=00000002314====="},
+		{"foofoofoofoobar", "31467557=0000000330460562"},
+	}
+	for _, tc := range testCases {
+		got := rleRoundTrip(t, []byte(tc.decoded))
+		testEqual(t, "RLEEncode(%q) = %q, want %q", tc.decoded, got, tc.encoded)
+	}
+}
+
+func TestRLEMixed(t *testing.T) {
+	rng := rand.New(rand.NewSource(962))
+
+	var raw []byte
+	for i := 0; i < 20; i++ {
+		random := make([]byte, rng.Intn(100))
+		rng.Read(random)
+		raw = append(raw, random...)
+		raw = append(raw, make([]byte, rng.Intn(10000))...)
+		raw = append(raw, bytes.Repeat([]byte{0xff}, rng.Intn(1000))...)
+	}
+	raw = append(raw, 1)
+
+	got := rleRoundTrip(t, raw)
+	if len(got) >= EncodedLen(len(raw)) {
+		t.Errorf("RLEEncode produced %d bytes, want fewer than %d", len(got), EncodedLen(len(raw)))
+	}
+
+	// Write in small chunks to exercise buffering across group boundaries.
+	for bs := 1; bs <= 4; bs++ {
+		encoded := new(bytes.Buffer)
+		w := NewRLEEncoder(encoded)
+		for pos := 0; pos < len(raw); pos += bs {
+			end := pos + bs
+			if end > len(raw) {
+				end = len(raw)
+			}
+			w.Write(raw[pos:end])
+		}
+		w.Close()
+		testEqual(t, "RLEEncode/%d = %q, want %q", bs, encoded.String(), got)
+	}
+}
+
+func TestRLEDecodeCorrupt(t *testing.T) {
+	testCases := []struct {
+		input  string
+		offset int
+	}{
+		{"=00000002", 0},
+		{"00000000=00000000", 9},
+		{"00000000=00000008", 16},
+		{"314=====", -1},
+		{"00000008", 7},
+	}
+	for _, tc := range testCases {
+		_, err := ioutil.ReadAll(NewRLEDecoder(strings.NewReader(tc.input)))
+		if tc.offset == -1 {
+			if err != nil {
+				t.Errorf("RLEDecoder wrongly detected corruption in %q: %v", tc.input, err)
+			}
+			continue
+		}
+		switch err := err.(type) {
+		case CorruptInputError:
+			testEqual(t, "Corruption in %q at offset %v, want %v", tc.input, int(err.Offset()), tc.offset)
+		default:
+			t.Errorf("RLEDecoder failed to detect corruption in %q: %v", tc.input, err)
+		}
+	}
+}
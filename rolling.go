@@ -0,0 +1,80 @@
+package base8
+
+import "io"
+
+type rollingWriter struct {
+	open  func(index int) (io.WriteCloser, error)
+	limit int64 // maximum bytes per file, a multiple of 8
+	cur   io.WriteCloser
+	index int   // index of the next file
+	n     int64 // bytes written to cur
+}
+
+func (w *rollingWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if w.cur != nil && w.n == w.limit {
+			err = w.cur.Close()
+			w.cur = nil
+			if err != nil {
+				return n, err
+			}
+		}
+		if w.cur == nil {
+			if w.cur, err = w.open(w.index); err != nil {
+				w.cur = nil
+				return n, err
+			}
+			w.index++
+			w.n = 0
+		}
+
+		nn := int64(len(p))
+		if nn > w.limit-w.n {
+			nn = w.limit - w.n
+		}
+		m, err := w.cur.Write(p[0:nn])
+		n += m
+		w.n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+type rollingEncoder struct {
+	io.WriteCloser
+	w *rollingWriter
+}
+
+// Close flushes any pending output from the encoder and closes the last
+// output file.
+func (e *rollingEncoder) Close() error {
+	err := e.WriteCloser.Close()
+	if e.w.cur != nil {
+		if cerr := e.w.cur.Close(); err == nil {
+			err = cerr
+		}
+		e.w.cur = nil
+	}
+	return err
+}
+
+// NewRollingEncoder returns a new base8 stream encoder that spreads its
+// output across a sequence of files. open is called with successive indices,
+// starting at 0, to create each file when there is data to write to it. Once
+// a file holds maxEncodedBytesPerFile bytes, rounded down to a multiple of 8
+// but no less than 8, it is closed and the next file is opened. Files are
+// therefore split on quantum boundaries and each one is independently
+// decodable; only the last file carries the final padding. When finished
+// writing, the caller must Close the returned encoder to flush any partially
+// written blocks and close the last file.
+func NewRollingEncoder(open func(index int) (io.WriteCloser, error), maxEncodedBytesPerFile int64) io.WriteCloser {
+	limit := maxEncodedBytesPerFile / 8 * 8
+	if limit < 8 {
+		limit = 8
+	}
+	w := &rollingWriter{open: open, limit: limit}
+	return &rollingEncoder{WriteCloser: NewEncoder(w), w: w}
+}
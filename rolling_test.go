@@ -0,0 +1,86 @@
+package base8
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type memFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestRollingEncoder(t *testing.T) {
+	raw := bytes.Repeat([]byte(bigtest.decoded), 10)
+	for _, limit := range []int64{0, 8, 20, 64, 1 << 20} {
+		var files []*memFile
+		open := func(index int) (io.WriteCloser, error) {
+			testEqual(t, "open(%d), want index %d", index, len(files))
+			f := &memFile{}
+			files = append(files, f)
+			return f, nil
+		}
+
+		w := NewRollingEncoder(open, limit)
+		for pos := 0; pos < len(raw); pos += 7 {
+			end := pos + 7
+			if end > len(raw) {
+				end = len(raw)
+			}
+			if _, err := w.Write(raw[pos:end]); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		max := limit / 8 * 8
+		if max < 8 {
+			max = 8
+		}
+		var joined []byte
+		for i, f := range files {
+			if !f.closed {
+				t.Errorf("limit %d: file %d not closed", limit, i)
+			}
+			if int64(f.Len()) > max || f.Len()%8 != 0 || i < len(files)-1 && int64(f.Len()) != max {
+				t.Errorf("limit %d: file %d has length %d", limit, i, f.Len())
+			}
+			if i < len(files)-1 {
+				if _, err := DecodeString(f.String()); err != nil || bytes.IndexByte(f.Bytes(), PadChar) != -1 {
+					t.Errorf("limit %d: file %d = %q is not independently decodable", limit, i, f.String())
+				}
+			}
+			joined = append(joined, f.Bytes()...)
+		}
+		decoded, err := DecodeString(string(joined))
+		if err != nil || !bytes.Equal(decoded, raw) {
+			t.Errorf("limit %d: decoding concatenated files failed: %v", limit, err)
+		}
+	}
+}
+
+func TestRollingEncoderOpenError(t *testing.T) {
+	want := errors.New("no space")
+	opened := 0
+	open := func(index int) (io.WriteCloser, error) {
+		if index == 2 {
+			return nil, want
+		}
+		opened++
+		return &memFile{}, nil
+	}
+	w := NewRollingEncoder(open, 8)
+	w.Write([]byte(bigtest.decoded))
+	err := w.Close()
+	testEqual(t, "Close() = %v, want %v", err, want)
+	testEqual(t, "opened %d files, want %d", opened, 2)
+}
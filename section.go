@@ -0,0 +1,68 @@
+package base8
+
+import (
+	"errors"
+	"io"
+)
+
+// errNegativeOffset is returned by ReadAt for a negative offset.
+var errNegativeOffset = errors.New("base8: negative offset")
+
+type decoderAt struct {
+	enc *Encoding
+	r   io.ReaderAt
+}
+
+// ReadAt reads len(p) decoded bytes starting at offset off of the decoded
+// data. It reads from the underlying io.ReaderAt only the quanta that hold
+// those bytes, plus one byte to tell whether the last of them is the final
+// quantum.
+func (d *decoderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+
+	// Every 3 decoded bytes are held by 8 encoded ones.
+	skip := int(off % 3)
+	lo := off / 3 * 8
+	buf := make([]byte, (skip+len(p)+2)/3*8+1)
+	m, rerr := d.r.ReadAt(buf, lo)
+	final := m < len(buf)
+	if final && rerr != io.EOF {
+		return 0, rerr
+	}
+	src := buf[0:min(m, len(buf)-1)]
+
+	dbuf := make([]byte, d.enc.DecodedLen(len(src)))
+	dn, err := decodeAt(d.enc, dbuf, src, lo, final)
+	if dn > skip {
+		n = copy(p, dbuf[skip:dn])
+	}
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// NewDecoderAt returns an io.ReaderAt that reads the data decoded from the
+// base8 data in r. Because every 8 encoded bytes decode to exactly 3 bytes,
+// any range of the decoded data can be read without decoding what precedes
+// it, for example to seek within a large encoded archive. The offset of a
+// CorruptInputError returned by ReadAt is an offset into r. ReadAt may be
+// called concurrently if r's ReadAt may be.
+//
+// The decoded data ends at the padded final quantum, or for an encoding that
+// uses NoPadding, at the end of r. enc must not ignore any characters, since
+// their positions would not be known without reading the whole input.
+func (enc *Encoding) NewDecoderAt(r io.ReaderAt) io.ReaderAt {
+	if enc.ignores {
+		panic("random access decoding with ignored characters")
+	}
+	return &decoderAt{enc: enc, r: r}
+}
+
+// NewDecoderAt returns an io.ReaderAt that reads the data decoded from the
+// standard base8 data in r.
+func NewDecoderAt(r io.ReaderAt) io.ReaderAt {
+	return StdEncoding.NewDecoderAt(r)
+}
@@ -0,0 +1,64 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoderAt(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 10)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, StdEncoding.WithBitOrder(LSBFirst), StdEncoding.WithLengthEscape()} {
+		for _, size := range []int{0, 1, 2, 3, 4, 5, len(input)} {
+			data := input[0:size]
+			r := enc.NewDecoderAt(strings.NewReader(enc.EncodeToString(data)))
+			for off := 0; off <= size+1; off++ {
+				for _, n := range []int{0, 1, 2, 3, 4, 7, size} {
+					p := make([]byte, n)
+					got, err := r.ReadAt(p, int64(off))
+
+					want := data[min(off, size):min(off+n, size)]
+					wantErr := error(nil)
+					if len(want) < n {
+						wantErr = io.EOF
+					}
+					if got != len(want) || err != wantErr || !bytes.Equal(p[0:got], want) {
+						t.Errorf("ReadAt(%d bytes, %d) of %d bytes = %q, %v, want %q, %v", n, off, size, p[0:got], err, want, wantErr)
+					}
+				}
+			}
+		}
+	}
+
+	// Reading all of the decoded data with io.SectionReader.
+	encoded := EncodeToString(input)
+	got, err := io.ReadAll(io.NewSectionReader(NewDecoderAt(strings.NewReader(encoded)), 0, int64(len(input))))
+	testEqual(t, "ReadAll(NewDecoderAt) = error %v, want %v", err, error(nil))
+	testEqual(t, "ReadAll(NewDecoderAt) = %q, want %q", string(got), string(input))
+
+	_, err = NewDecoderAt(strings.NewReader(encoded)).ReadAt(make([]byte, 1), -1)
+	testEqual(t, "ReadAt(-1) = %v, want %v", err, errNegativeOffset)
+}
+
+func TestDecoderAtCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		encoded string
+		off     int64
+		want    string
+		offset  int64
+	}{
+		{"3146755730460562", 3, "bar", -1},
+		{"31467557304x0562", 3, "", 11},
+		{"31467557304x0562", 0, "foo", -1},
+		{"314=====31467557", 0, "", 3},
+		{"31467557304604==", 3, "ba", -1},
+		{"31467557304604=", 3, "", 15},
+		{"3146755730", 3, "", 8},
+	} {
+		p := make([]byte, 3)
+		n, err := NewDecoderAt(strings.NewReader(tc.encoded)).ReadAt(p, tc.off)
+		testEqual(t, "ReadAt(%q, %d) = %q, want %q", tc.encoded, tc.off, string(p[0:n]), tc.want)
+		testEqual(t, "ReadAt(%q, %d) corrupt at %d, want %d", tc.encoded, tc.off, corruptOffset(err), tc.offset)
+	}
+}
@@ -0,0 +1,173 @@
+package base8
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errWhence       = errors.New("base8: invalid whence")
+	errNegativeSeek = errors.New("base8: negative position")
+)
+
+type seekingDecoder struct {
+	rs    io.ReadSeeker
+	d     *decoder
+	width int64 // line width, or 0 if the input is not wrapped
+	eol   int64 // length of the line ending
+	pos   int64 // decoded offset of the next byte returned by Read
+	skip  int   // decoded bytes to discard before the next Read
+	size  int64 // decoded length, or -1 if not yet known
+}
+
+// encodedOffset returns the offset in rs of the data character with index k.
+func (s *seekingDecoder) encodedOffset(k int64) int64 {
+	if s.width == 0 {
+		return k
+	}
+	return k + k/s.width*s.eol
+}
+
+// reset makes the decoder read from the quantum that starts with the data
+// character with index k.
+func (s *seekingDecoder) reset(k int64) error {
+	off := s.encodedOffset(k)
+	if _, err := s.rs.Seek(off, io.SeekStart); err != nil {
+		return err
+	}
+
+	// Report corrupt input at offsets into rs rather than from the point
+	// at which decoding resumed.
+	s.d.Reset(s.rs)
+	if s.d.ign != nil {
+		s.d.ign.base = off
+	} else {
+		s.d.offset = off
+	}
+	return nil
+}
+
+// decodedSize returns the length of the decoded data, which for a padded
+// encoding is found by decoding the final quantum.
+func (s *seekingDecoder) decodedSize() (int64, error) {
+	if s.size >= 0 {
+		return s.size, nil
+	}
+	end, err := s.rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	// Count the data characters, given that every line, including the
+	// last, is terminated by a line ending.
+	n := end
+	if s.width > 0 {
+		n = end / (s.width + s.eol) * s.width
+		if rem := end % (s.width + s.eol); rem > s.eol {
+			n += rem - s.eol
+		}
+	}
+
+	if s.d.enc.padChar == NoPadding || n < 8 {
+		s.size = s.d.enc.DecodedLen64(n)
+		return s.size, nil
+	}
+	last := n/8*8 - 8
+	if err := s.reset(last); err != nil {
+		return 0, err
+	}
+	tail, err := io.ReadAll(s.d)
+	if err != nil {
+		return 0, err
+	}
+	s.size = last/8*3 + int64(len(tail))
+	return s.size, nil
+}
+
+func (s *seekingDecoder) Read(p []byte) (n int, err error) {
+	for s.skip > 0 {
+		var buf [2]byte
+		n, err := s.d.Read(buf[0:s.skip])
+		s.skip -= n
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err = s.d.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+// Seek sets the decoded offset for the next Read to offset, interpreted
+// according to whence. Seeking relative to the end reads the final quantum to
+// find the decoded length, which is then remembered. Decoding resumes at the
+// quantum that holds the new offset; no other data is read.
+func (s *seekingDecoder) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		size, err := s.decodedSize()
+		if err != nil {
+			return 0, err
+		}
+		offset += size
+	default:
+		return 0, errWhence
+	}
+	if offset < 0 {
+		return 0, errNegativeSeek
+	}
+
+	if err := s.reset(offset / 3 * 8); err != nil {
+		return 0, err
+	}
+	s.pos, s.skip = offset, int(offset%3)
+	return offset, nil
+}
+
+// NewSeekableDecoder constructs a new base8 stream decoder that reads from rs
+// and implements io.Seeker. Because every 8 encoded bytes decode to exactly 3
+// bytes, a decoded offset maps directly to an offset in rs, so seeking reads
+// nothing but the quantum holding the new offset. The encoded data must
+// occupy all of rs, starting at offset 0, and rs must initially be positioned
+// there. The offset of a CorruptInputError it returns is an offset into rs.
+//
+// enc must not ignore any characters, since their positions would not be
+// known without reading the whole input; use NewSeekableWrappingDecoder for
+// the output of NewWrappingEncoder.
+func (enc *Encoding) NewSeekableDecoder(rs io.ReadSeeker) io.ReadSeeker {
+	if enc.ignores {
+		panic("seekable decoding with ignored characters")
+	}
+	return &seekingDecoder{rs: rs, d: enc.NewDecoder(rs).(*decoder), size: -1}
+}
+
+// NewSeekableDecoder constructs a new seekable base8 stream decoder that uses
+// the standard encoding.
+func NewSeekableDecoder(rs io.ReadSeeker) io.ReadSeeker {
+	return StdEncoding.NewSeekableDecoder(rs)
+}
+
+// NewSeekableWrappingDecoder is like NewSeekableDecoder, but reads the output
+// of NewWrappingEncoder for the same width and lineEnding: lines of width
+// characters, each, including the last, terminated by lineEnding. Offsets in
+// rs are computed from the line layout, so input wrapped in any other way is
+// decoded incorrectly after a seek. width must be positive.
+func (enc *Encoding) NewSeekableWrappingDecoder(rs io.ReadSeeker, width int, lineEnding string) io.ReadSeeker {
+	if width <= 0 {
+		panic("invalid line width")
+	}
+	if enc.ignores {
+		panic("seekable decoding with ignored characters")
+	}
+	d := enc.NewWrappingDecoder(rs, lineEnding).(*decoder)
+	return &seekingDecoder{rs: rs, d: d, width: int64(width), eol: int64(len(lineEnding)), size: -1}
+}
+
+// NewSeekableWrappingDecoder is like NewSeekableDecoder, but reads the output
+// of NewWrappingEncoder that uses the standard encoding.
+func NewSeekableWrappingDecoder(rs io.ReadSeeker, width int, lineEnding string) io.ReadSeeker {
+	return StdEncoding.NewSeekableWrappingDecoder(rs, width, lineEnding)
+}
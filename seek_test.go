@@ -0,0 +1,83 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSeekableDecoder(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 10)
+	for _, size := range []int{0, 1, 2, 3, 4, 5, len(input)} {
+		data := input[0:size]
+		for name, newDecoder := range map[string]func() io.ReadSeeker{
+			"std": func() io.ReadSeeker {
+				return NewSeekableDecoder(strings.NewReader(EncodeToString(data)))
+			},
+			"raw": func() io.ReadSeeker {
+				return RawEncoding.NewSeekableDecoder(strings.NewReader(RawEncoding.EncodeToString(data)))
+			},
+			"wrapped": func() io.ReadSeeker {
+				var buf bytes.Buffer
+				w := NewWrappingEncoder(&buf, 13, "\r\n")
+				w.Write(data)
+				w.Close()
+				return NewSeekableWrappingDecoder(bytes.NewReader(buf.Bytes()), 13, "\r\n")
+			},
+		} {
+			got, err := io.ReadAll(newDecoder())
+			testEqual(t, "%s: ReadAll of %d bytes = error %v, want %v", name, size, err, error(nil))
+			testEqual(t, "%s: ReadAll of %d bytes = %q, want %q", name, size, string(got), string(data))
+
+			r := newDecoder()
+			end, err := r.Seek(0, io.SeekEnd)
+			testEqual(t, "%s: Seek(0, SeekEnd) of %d bytes = error %v, want %v", name, size, err, error(nil))
+			testEqual(t, "%s: Seek(0, SeekEnd) of %d bytes = %d, want %d", name, size, end, int64(size))
+
+			for off := 0; off <= size; off++ {
+				for _, whence := range []int{io.SeekStart, io.SeekCurrent, io.SeekEnd} {
+					var pos int64
+					switch whence {
+					case io.SeekStart:
+						pos, err = r.Seek(int64(off), whence)
+					case io.SeekCurrent:
+						r.Seek(1, io.SeekStart)
+						pos, err = r.Seek(int64(off-1), whence)
+					case io.SeekEnd:
+						pos, err = r.Seek(int64(off-size), whence)
+					}
+					if pos != int64(off) || err != nil {
+						t.Fatalf("%s: Seek to %d (whence %d) of %d bytes = %d, %v", name, off, whence, size, pos, err)
+					}
+					p := make([]byte, 4)
+					n, _ := io.ReadFull(r, p)
+					want := data[off:min(off+4, size)]
+					if !bytes.Equal(p[0:n], want) {
+						t.Errorf("%s: read at %d (whence %d) of %d bytes = %q, want %q", name, off, whence, size, p[0:n], want)
+					}
+				}
+			}
+		}
+	}
+
+	r := NewSeekableDecoder(strings.NewReader(bigtest.encoded))
+	if _, err := r.Seek(-1, io.SeekStart); err != errNegativeSeek {
+		t.Errorf("Seek(-1, SeekStart) = %v, want %v", err, errNegativeSeek)
+	}
+	if _, err := r.Seek(0, 42); err != errWhence {
+		t.Errorf("Seek(0, 42) = %v, want %v", err, errWhence)
+	}
+}
+
+func TestSeekableDecoderCorrupt(t *testing.T) {
+	r := NewSeekableDecoder(strings.NewReader("314675573x460562"))
+	r.Seek(4, io.SeekStart)
+	_, err := io.ReadAll(r)
+	testEqual(t, "NewSeekableDecoder corrupt at offset %d, want %d", corruptOffset(err), int64(9))
+
+	r = NewSeekableWrappingDecoder(strings.NewReader("3146\n7557\n3x46\n0562\n"), 4, "\n")
+	r.Seek(4, io.SeekStart)
+	_, err = io.ReadAll(r)
+	testEqual(t, "NewSeekableWrappingDecoder corrupt at offset %d, want %d", corruptOffset(err), int64(11))
+}
@@ -0,0 +1,40 @@
+package base8
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// selfTestInput is the data whose prefixes SelfTest encodes. Its bytes set
+// and clear every bit of a quantum.
+const selfTestInput = "\xa5\x5a\xff\x00\x81\x7e"
+
+// SelfTest encodes and decodes the prefixes of a fixed input, of 0 through 6
+// bytes, with enc and returns an error describing the first that does not
+// round-trip, or whose encoding has the wrong length or contains a byte that
+// is neither a symbol of the alphabet nor the padding character. It is a
+// cheap check, for example at startup, that an encoding built with
+// NewEncoding, WithPadding and other options behaves as configured.
+func (enc *Encoding) SelfTest() error {
+	for n := 0; n <= len(selfTestInput); n++ {
+		src := []byte(selfTestInput[0:n])
+		encoded := enc.EncodeToString(src)
+		if len(encoded) != enc.EncodedLen(n) {
+			return fmt.Errorf("base8: self-test: encoding of %d bytes has length %d, want %d", n, len(encoded), enc.EncodedLen(n))
+		}
+		for i := 0; i < len(encoded); i++ {
+			c := encoded[i]
+			if bytes.IndexByte(enc.encode[0:], c) < 0 && (enc.padChar == NoPadding || rune(c) != enc.padChar) {
+				return fmt.Errorf("base8: self-test: encoding of %d bytes contains %q at offset %d", n, c, i)
+			}
+		}
+		decoded, err := enc.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("base8: self-test: decoding %q: %w", encoded, err)
+		}
+		if !bytes.Equal(decoded, src) {
+			return fmt.Errorf("base8: self-test: %q decodes to %x, want %x", encoded, decoded, src)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package base8
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	for _, enc := range []*Encoding{
+		StdEncoding,
+		RawEncoding,
+		LetterEncoding.WithPadding('.'),
+		NewEncoding("qwertyui").WithBitOrder(LSBFirst).WithIgnoredChars(" "),
+	} {
+		testEqual(t, "SelfTest() = %v, want %v", enc.SelfTest(), error(nil))
+	}
+}
+
+func TestSelfTestBroken(t *testing.T) {
+	// The constructors reject these encodings, so they are built directly,
+	// from an alphabet that does not use the SWAR fast paths.
+	swapped := *LetterEncoding
+	swapped.decodeMap['c'], swapped.decodeMap['d'] = 3, 2
+	padded := *LetterEncoding
+	padded.padChar = 'c'
+	swar := *NewEncoding("qwertyui")
+	swar.swar = true
+	missing := *LetterEncoding
+	missing.decodeMap['h'] = invalidIndex
+
+	for _, tc := range []struct {
+		enc  *Encoding
+		want string
+	}{
+		{&swapped, "decodes to"},
+		{&padded, "decodes to"},
+		{&missing, "decoding"},
+		{&swar, "contains"},
+	} {
+		err := tc.enc.SelfTest()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("SelfTest() = %v, want an error containing %q", err, tc.want)
+		}
+	}
+	var cie CorruptInputError
+	testEqual(t, "SelfTest() wraps CorruptInputError = %v, want %v", errors.As(missing.SelfTest(), &cie), true)
+}
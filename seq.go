@@ -0,0 +1,97 @@
+package base8
+
+import "iter"
+
+// EncodeSeq returns an iterator over the base8 encoding of src, including
+// any trailing padding.
+func (enc *Encoding) EncodeSeq(src []byte) iter.Seq[byte] {
+	return func(yield func(byte) bool) {
+		for len(src) > 0 {
+			var q [8]byte
+			n := len(src)
+			if n > 3 {
+				n = 3
+			}
+			enc.Encode(q[0:], src[0:n])
+			for _, c := range q[0:enc.EncodedLen(n)] {
+				if !yield(c) {
+					return
+				}
+			}
+			src = src[n:]
+		}
+	}
+}
+
+// DecodeSeq returns an iterator over the bytes represented by the base8
+// string s. The iterator yields each decoded byte with a nil error. If s
+// contains invalid base8 data, the iterator yields the bytes decoded before
+// the corruption was detected followed by a single zero byte paired with a
+// CorruptInputError, and then stops.
+func (enc *Encoding) DecodeSeq(s string) iter.Seq2[byte, error] {
+	if enc.ignores {
+		return func(yield func(byte, error) bool) {
+			for b, err := range decodeSeq(enc, string(stripIgnored(enc, s))) {
+				if !yield(b, unstrip(enc, s, err)) {
+					return
+				}
+			}
+		}
+	}
+	return decodeSeq(enc, s)
+}
+
+// decodeSeq implements DecodeSeq for input that contains no ignored
+// characters.
+func decodeSeq(enc *Encoding, s string) iter.Seq2[byte, error] {
+	return func(yield func(byte, error) bool) {
+		i := 0
+
+		// Padding is only legal in the last quantum, so any quantum that is
+		// followed by at least one more complete quantum must consist
+		// entirely of symbols.
+		for ; len(s)-i >= 16; i += 8 {
+			var q [8]byte
+			for j := 0; j < 8; j++ {
+				q[j] = enc.decodeMap[s[i+j]]
+				if q[j] > 7 {
+					yield(0, enc.invalidSymbol(i+j, s[i+j]))
+					return
+				}
+			}
+			b0, b1, b2 := q[0]<<5|q[1]<<2|q[2]>>1, q[2]<<7|q[3]<<4|q[4]<<1|q[5]>>2, q[5]<<6|q[6]<<3|q[7]
+			if enc.lsb {
+				b0 = q[0] | q[1]<<3 | q[2]<<6
+				b1 = q[2]>>2 | q[3]<<1 | q[4]<<4 | q[5]<<7
+				b2 = q[5]>>1 | q[6]<<2 | q[7]<<5
+			}
+			if !yield(b0, nil) || !yield(b1, nil) || !yield(b2, nil) {
+				return
+			}
+		}
+
+		// Decode the remaining (at most two) quanta at once.
+		var tail [16]byte
+		var dbuf [6]byte
+		n, _, err := decodeStripped(enc, dbuf[0:], tail[0:copy(tail[0:], s[i:])])
+		for _, b := range dbuf[0:n] {
+			if !yield(b, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(0, err.(CorruptInputError).shift(int64(i)))
+		}
+	}
+}
+
+// EncodeSeq returns an iterator over the standard base8 encoding of src.
+func EncodeSeq(src []byte) iter.Seq[byte] {
+	return StdEncoding.EncodeSeq(src)
+}
+
+// DecodeSeq returns an iterator over the bytes represented by the standard
+// base8 string s.
+func DecodeSeq(s string) iter.Seq2[byte, error] {
+	return StdEncoding.DecodeSeq(s)
+}
@@ -0,0 +1,127 @@
+package base8
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSeq(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		var got []byte
+		for c := range EncodeSeq([]byte(p.decoded)) {
+			got = append(got, c)
+		}
+		testEqual(t, "EncodeSeq(%q) = %q, want %q", p.decoded, string(got), p.encoded)
+		testEqual(t, "EncodeSeq(%q) = %q, want %q", p.decoded, string(got), EncodeToString([]byte(p.decoded)))
+	}
+}
+
+func TestEncodeSeqEncodings(t *testing.T) {
+	for _, enc := range []*Encoding{RawEncoding, StdEncoding.WithBitOrder(LSBFirst), RawEncoding.WithBitOrder(LSBFirst)} {
+		for _, p := range append(pairs, bigtest) {
+			var got []byte
+			for c := range enc.EncodeSeq([]byte(p.decoded)) {
+				got = append(got, c)
+			}
+			testEqual(t, "EncodeSeq(%q) = %q, want %q", p.decoded, string(got), enc.EncodeToString([]byte(p.decoded)))
+		}
+	}
+}
+
+func TestEncodeSeqBreak(t *testing.T) {
+	var got []byte
+	for c := range EncodeSeq([]byte(bigtest.decoded)) {
+		got = append(got, c)
+		if len(got) == 10 {
+			break
+		}
+	}
+	testEqual(t, "EncodeSeq(%q) = %q, want %q", bigtest.decoded, string(got), bigtest.encoded[:10])
+}
+
+func collectDecodeSeq(s string) ([]byte, error) {
+	var out []byte
+	for b, err := range DecodeSeq(s) {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func TestDecodeSeq(t *testing.T) {
+	inputs := []string{bigtest.encoded, strings.Repeat(bigtest.encoded[:24], 10) + "314====="}
+	for _, p := range pairs {
+		inputs = append(inputs, p.encoded)
+	}
+	for _, in := range inputs {
+		want, err := DecodeString(in)
+		if err != nil {
+			t.Fatalf("DecodeString(%q) failed: %v", in, err)
+		}
+		got, err := collectDecodeSeq(in)
+		if err != nil {
+			t.Errorf("DecodeSeq(%q) failed: %v", in, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("DecodeSeq(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDecodeSeqCorrupt(t *testing.T) {
+	testCases := []string{
+		"!!!!",
+		"11=1====",
+		"222222222",
+		"1111====",
+		"31467557314=====31467557",
+		"3146755731467557314675573146755!",
+		"31467557314675573146755731467=5=",
+	}
+	for _, in := range testCases {
+		dbuf := make([]byte, DecodedLen(len(in)))
+		n, wantErr := Decode(dbuf, []byte(in))
+		got, err := collectDecodeSeq(in)
+		testEqual(t, "DecodeSeq(%q) = error %v, want %v", in, err, wantErr)
+		testEqual(t, "DecodeSeq(%q) = %q, want %q", in, string(got), string(dbuf[:n]))
+	}
+}
+
+func TestDecodeSeqLengthEscape(t *testing.T) {
+	enc := StdEncoding.WithLengthEscape()
+	inputs := []string{"00201003=1010", "302611433106254631664151=1324", "=2314674", "=2314674x", "31467557=1x04", "=13140000"}
+	for _, p := range append(pairs, bigtest) {
+		inputs = append(inputs, enc.EncodeToString([]byte(p.decoded)))
+	}
+	for _, in := range inputs {
+		dbuf := make([]byte, enc.DecodedLen(len(in)))
+		n, wantErr := enc.Decode(dbuf, []byte(in))
+		var got []byte
+		var err error
+		for b, berr := range enc.DecodeSeq(in) {
+			if err = berr; err != nil {
+				break
+			}
+			got = append(got, b)
+		}
+		testEqual(t, "DecodeSeq(%q) = error %v, want %v", in, err, wantErr)
+		testEqual(t, "DecodeSeq(%q) = %q, want %q", in, string(got), string(dbuf[:n]))
+	}
+}
+
+func TestDecodeSeqBreak(t *testing.T) {
+	var got []byte
+	for b, err := range DecodeSeq(bigtest.encoded) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, b)
+		if len(got) == 4 {
+			break
+		}
+	}
+	testEqual(t, "DecodeSeq(%q) = %q, want %q", bigtest.encoded, string(got), bigtest.decoded[:4])
+}
@@ -0,0 +1,46 @@
+package base8
+
+import "unsafe"
+
+// simdMasks holds the byte shuffles used by encodeSIMD and decodeSIMD for one
+// bit order. Each shuffle operates on pairs of 64-bit lanes;
+// an index of 0x80 clears the byte.
+type simdMasks struct {
+	// load moves each 3-byte group into the low bytes of a 64-bit lane as a
+	// little-endian integer whose lowest 3 bits hold the last symbol.
+	load [32]byte
+
+	// order reverses the bytes of each 64-bit lane for MSBFirst, so that
+	// symbol values in the lane's bytes are in output order.
+	order [32]byte
+
+	// pack moves the low 3 bytes of each 64-bit lane, in output order, to
+	// the first 6 bytes of each 128-bit lane.
+	pack [32]byte
+}
+
+var simdMSBFirst = simdMasks{
+	load:  [32]byte{2, 1, 0, 0x80, 0x80, 0x80, 0x80, 0x80, 5, 4, 3, 0x80, 0x80, 0x80, 0x80, 0x80, 2, 1, 0, 0x80, 0x80, 0x80, 0x80, 0x80, 5, 4, 3, 0x80, 0x80, 0x80, 0x80, 0x80},
+	order: [32]byte{7, 6, 5, 4, 3, 2, 1, 0, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0, 15, 14, 13, 12, 11, 10, 9, 8},
+	pack:  [32]byte{2, 1, 0, 10, 9, 8, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 2, 1, 0, 10, 9, 8, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+}
+
+var simdLSBFirst = simdMasks{
+	load:  [32]byte{0, 1, 2, 0x80, 0x80, 0x80, 0x80, 0x80, 3, 4, 5, 0x80, 0x80, 0x80, 0x80, 0x80, 0, 1, 2, 0x80, 0x80, 0x80, 0x80, 0x80, 3, 4, 5, 0x80, 0x80, 0x80, 0x80, 0x80},
+	order: [32]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	pack:  [32]byte{0, 1, 2, 8, 9, 10, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0, 1, 2, 8, 9, 10, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80},
+}
+
+// masks returns the shuffles for enc's bit order.
+func (enc *Encoding) masks() *simdMasks {
+	if enc.lsb {
+		return &simdLSBFirst
+	}
+	return &simdMSBFirst
+}
+
+// data returns a pointer to the first byte of s. Both strings and byte slices
+// begin with a pointer to their data.
+func data[T ~string | ~[]byte](s T) *byte {
+	return *(**byte)(unsafe.Pointer(&s))
+}
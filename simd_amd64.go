@@ -0,0 +1,43 @@
+//go:build !purego
+
+package base8
+
+// useSIMD reports whether encode and decode use encodeSIMD and decodeSIMD,
+// which here require AVX2.
+var useSIMD = hasAVX2()
+
+// hasAVX2 reports whether the CPU supports AVX2 and the operating system
+// saves the YMM registers.
+func hasAVX2() bool {
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&(osxsave|avx) != osxsave|avx {
+		return false
+	}
+	if eax, _ := xgetbv(); eax&6 != 6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&(1<<5) != 0
+}
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+// encodeSIMD encodes as many whole groups of the n bytes at src into dst as
+// it can handle efficiently, using the shuffle tables in masks, and returns
+// the number of bytes of src it encoded, which is a multiple of 3. The
+// alphabet of the encoding must permit the SWAR fast path, and first is its
+// first symbol.
+//
+//go:noescape
+func encodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+
+// decodeSIMD decodes whole quanta of the n bytes at src into dst, stopping at
+// the first block that contains a byte that is not a symbol, and returns the
+// number of bytes of src it decoded, which is a multiple of 8. The alphabet of
+// the encoding must permit the SWAR fast path, and first is its first symbol.
+//
+//go:noescape
+func decodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
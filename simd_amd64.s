@@ -0,0 +1,152 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// Masks that spread 24 bits over the 3-bit fields of 8 bytes, and back.
+DATA spread<>+0x00(SB)/8, $0x00000fff00000fff
+DATA spread<>+0x08(SB)/8, $0x003f003f003f003f
+DATA spread<>+0x10(SB)/8, $0x0707070707070707
+DATA spread<>+0x18(SB)/8, $0x0000000000ffffff
+DATA spread<>+0x20(SB)/8, $0xf8f8f8f8f8f8f8f8
+GLOBL spread<>(SB), RODATA|NOPTR, $40
+
+// func encodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+//
+// Each iteration encodes 12 bytes, 6 in each 128-bit lane, into 32.
+TEXT ·encodeSIMD(SB), NOSPLIT, $0-64
+	MOVQ dst_base+0(FP), DI
+	MOVQ dst_len+8(FP), DX
+	MOVQ src+24(FP), SI
+	MOVQ n+32(FP), CX
+	MOVQ masks+48(FP), AX
+	XORQ R9, R9
+
+	VPBROADCASTB first+40(FP), Y7
+	VMOVDQU 0(AX), Y8
+	VMOVDQU 32(AX), Y9
+	VPBROADCASTQ spread<>+0x00(SB), Y10
+	VPBROADCASTQ spread<>+0x08(SB), Y11
+	VPBROADCASTQ spread<>+0x10(SB), Y12
+
+encodeLoop:
+	// The second lane loads 16 bytes from src+6.
+	CMPQ CX, $24
+	JB   encodeDone
+	CMPQ DX, $32
+	JB   encodeDone
+
+	VMOVDQU     (SI), X0
+	VINSERTI128 $1, 6(SI), Y0, Y0
+	VPSHUFB     Y8, Y0, Y0
+
+	VPSLLQ $20, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y10, Y0, Y0
+	VPSLLQ $10, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y11, Y0, Y0
+	VPSLLQ $5, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y12, Y0, Y0
+
+	VPSHUFB Y9, Y0, Y0
+	VPOR    Y7, Y0, Y0
+	VMOVDQU Y0, (DI)
+
+	ADDQ $12, SI
+	SUBQ $12, CX
+	ADDQ $32, DI
+	SUBQ $32, DX
+	ADDQ $12, R9
+	JMP  encodeLoop
+
+encodeDone:
+	VZEROUPPER
+	MOVQ R9, nsrc+56(FP)
+	RET
+
+// func decodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+//
+// Each iteration decodes 32 bytes, 16 in each 128-bit lane, into 12.
+TEXT ·decodeSIMD(SB), NOSPLIT, $0-64
+	MOVQ dst_base+0(FP), DI
+	MOVQ dst_len+8(FP), DX
+	MOVQ src+24(FP), SI
+	MOVQ n+32(FP), CX
+	MOVQ masks+48(FP), AX
+	XORQ R9, R9
+
+	VPBROADCASTB first+40(FP), Y7
+	VMOVDQU 32(AX), Y9
+	VMOVDQU 64(AX), Y8
+	VPBROADCASTQ spread<>+0x00(SB), Y10
+	VPBROADCASTQ spread<>+0x08(SB), Y11
+	VPBROADCASTQ spread<>+0x18(SB), Y12
+	VPBROADCASTQ spread<>+0x20(SB), Y13
+
+decodeLoop:
+	CMPQ CX, $32
+	JB   decodeDone
+	CMPQ DX, $12
+	JB   decodeDone
+
+	// A byte is a symbol iff its top five bits match those of the first
+	// symbol.
+	VMOVDQU (SI), Y0
+	VPXOR   Y7, Y0, Y0
+	VPTEST  Y13, Y0
+	JNZ     decodeDone
+
+	VPSHUFB Y9, Y0, Y0
+
+	VPSRLQ $5, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y11, Y0, Y0
+	VPSRLQ $10, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y10, Y0, Y0
+	VPSRLQ $20, Y0, Y1
+	VPOR   Y1, Y0, Y0
+	VPAND  Y12, Y0, Y0
+
+	// Store exactly the 6 bytes of each lane: the first lane's two zero
+	// bytes are overwritten by the second, whose 6 bytes are stored as 4 and
+	// 2 so that nothing is written past the 12 decoded bytes.
+	VPSHUFB      Y8, Y0, Y0
+	VEXTRACTI128 $1, Y0, X1
+	MOVQ         X0, (DI)
+	MOVQ         X1, R10
+	MOVL         R10, 6(DI)
+	SHRQ         $32, R10
+	MOVW         R10, 10(DI)
+
+	ADDQ $32, SI
+	SUBQ $32, CX
+	ADDQ $12, DI
+	SUBQ $12, DX
+	ADDQ $32, R9
+	JMP  decodeLoop
+
+decodeDone:
+	VZEROUPPER
+	MOVQ R9, nsrc+56(FP)
+	RET
@@ -0,0 +1,24 @@
+//go:build !purego
+
+package base8
+
+// useSIMD reports whether encode and decode use encodeSIMD and decodeSIMD.
+// NEON is part of the arm64 baseline, so no feature detection is needed.
+const useSIMD = true
+
+// encodeSIMD encodes as many whole groups of the n bytes at src into dst as
+// it can handle efficiently, using the first 128-bit lane of the shuffle
+// tables in masks, and returns the number of bytes of src it encoded, which
+// is a multiple of 3. The alphabet of the encoding must permit the SWAR fast
+// path, and first is its first symbol.
+//
+//go:noescape
+func encodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+
+// decodeSIMD decodes whole quanta of the n bytes at src into dst, stopping at
+// the first block that contains a byte that is not a symbol, and returns the
+// number of bytes of src it decoded, which is a multiple of 8. The alphabet of
+// the encoding must permit the SWAR fast path, and first is its first symbol.
+//
+//go:noescape
+func decodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
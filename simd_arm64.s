@@ -0,0 +1,133 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func encodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+//
+// Each iteration encodes 6 bytes into 16. TBL clears the bytes whose index
+// is out of range, as PSHUFB does for indices with the top bit set.
+TEXT ·encodeSIMD(SB), NOSPLIT, $0-64
+	MOVD  dst_base+0(FP), R0
+	MOVD  dst_len+8(FP), R1
+	MOVD  src+24(FP), R2
+	MOVD  n+32(FP), R3
+	MOVBU first+40(FP), R4
+	MOVD  masks+48(FP), R5
+	MOVD  ZR, R9
+
+	VDUP R4, V7.B16
+	VLD1 (R5), [V8.B16]
+	ADD  $32, R5, R6
+	VLD1 (R6), [V9.B16]
+	MOVD $0x00000fff00000fff, R7
+	VDUP R7, V10.D2
+	MOVD $0x003f003f003f003f, R7
+	VDUP R7, V11.D2
+	MOVD $0x0707070707070707, R7
+	VDUP R7, V12.D2
+
+encodeLoop:
+	// Each load reads 16 bytes of src.
+	CMP $16, R3
+	BLT encodeDone
+	CMP $16, R1
+	BLT encodeDone
+
+	VLD1 (R2), [V0.B16]
+	VTBL V8.B16, [V0.B16], V0.B16
+
+	VSHL $20, V0.D2, V1.D2
+	VORR V1.B16, V0.B16, V0.B16
+	VAND V10.B16, V0.B16, V0.B16
+	VSHL $10, V0.D2, V1.D2
+	VORR V1.B16, V0.B16, V0.B16
+	VAND V11.B16, V0.B16, V0.B16
+	VSHL $5, V0.D2, V1.D2
+	VORR V1.B16, V0.B16, V0.B16
+	VAND V12.B16, V0.B16, V0.B16
+
+	VTBL   V9.B16, [V0.B16], V0.B16
+	VORR   V7.B16, V0.B16, V0.B16
+	VST1.P [V0.B16], 16(R0)
+
+	ADD $6, R2
+	SUB $6, R3
+	SUB $16, R1
+	ADD $6, R9
+	B   encodeLoop
+
+encodeDone:
+	MOVD R9, nsrc+56(FP)
+	RET
+
+// func decodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int)
+//
+// Each iteration decodes 16 bytes into 6.
+TEXT ·decodeSIMD(SB), NOSPLIT, $0-64
+	MOVD  dst_base+0(FP), R0
+	MOVD  dst_len+8(FP), R1
+	MOVD  src+24(FP), R2
+	MOVD  n+32(FP), R3
+	MOVBU first+40(FP), R4
+	MOVD  masks+48(FP), R5
+	MOVD  ZR, R9
+
+	VDUP R4, V7.B16
+	ADD  $32, R5, R6
+	VLD1 (R6), [V9.B16]
+	ADD  $64, R5, R6
+	VLD1 (R6), [V8.B16]
+	MOVD $0x00000fff00000fff, R7
+	VDUP R7, V10.D2
+	MOVD $0x003f003f003f003f, R7
+	VDUP R7, V11.D2
+	MOVD $0x0000000000ffffff, R7
+	VDUP R7, V12.D2
+	MOVD $0xf8f8f8f8f8f8f8f8, R7
+	VDUP R7, V13.D2
+
+decodeLoop:
+	CMP $16, R3
+	BLT decodeDone
+	CMP $6, R1
+	BLT decodeDone
+
+	// A byte is a symbol iff its top five bits match those of the first
+	// symbol.
+	VLD1 (R2), [V0.B16]
+	VEOR V7.B16, V0.B16, V0.B16
+	VAND V13.B16, V0.B16, V1.B16
+	VMOV V1.D[0], R10
+	VMOV V1.D[1], R11
+	ORR  R10, R11, R11
+	CBNZ R11, decodeDone
+
+	VTBL V9.B16, [V0.B16], V0.B16
+
+	VUSHR $5, V0.D2, V1.D2
+	VORR  V1.B16, V0.B16, V0.B16
+	VAND  V11.B16, V0.B16, V0.B16
+	VUSHR $10, V0.D2, V1.D2
+	VORR  V1.B16, V0.B16, V0.B16
+	VAND  V10.B16, V0.B16, V0.B16
+	VUSHR $20, V0.D2, V1.D2
+	VORR  V1.B16, V0.B16, V0.B16
+	VAND  V12.B16, V0.B16, V0.B16
+
+	VTBL V8.B16, [V0.B16], V0.B16
+	// Store exactly the 6 decoded bytes, as 4 and 2.
+	VMOV V0.D[0], R10
+	MOVW R10, (R0)
+	LSR  $32, R10
+	MOVH R10, 4(R0)
+
+	ADD $16, R2
+	SUB $16, R3
+	ADD $6, R0
+	SUB $6, R1
+	ADD $16, R9
+	B   decodeLoop
+
+decodeDone:
+	MOVD R9, nsrc+56(FP)
+	RET
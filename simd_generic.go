@@ -0,0 +1,15 @@
+//go:build !(amd64 || arm64) || purego
+
+package base8
+
+// useSIMD reports whether encode and decode use encodeSIMD and decodeSIMD,
+// which are not implemented on this platform.
+const useSIMD = false
+
+func encodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int) {
+	panic("unreachable")
+}
+
+func decodeSIMD(dst []byte, src *byte, n int, first byte, masks *simdMasks) (nsrc int) {
+	panic("unreachable")
+}
@@ -0,0 +1,80 @@
+package base8
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSIMD compares the vectorized paths, where available, against the
+// scalar ones for inputs that span several vector blocks, using destination
+// slices of exactly the encoded and decoded lengths.
+func TestSIMD(t *testing.T) {
+	src := make([]byte, 300)
+	rand.New(rand.NewSource(1556)).Read(src)
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		fast := StdEncoding.WithBitOrder(order)
+		slow := *fast
+		slow.swar = false
+		for n := 0; n <= len(src); n++ {
+			want := make([]byte, slow.EncodedLen(n))
+			slow.Encode(want, src[0:n])
+			got := make([]byte, fast.EncodedLen(n))
+			fast.Encode(got, src[0:n])
+			testEqual(t, "SIMD Encode(%q) = %q, want %q", src[0:n], string(got), string(want))
+
+			dbuf := make([]byte, n)
+			m, err := fast.Decode(dbuf, want)
+			testEqual(t, "SIMD Decode(%q) = error %v, want %v", want, err, error(nil))
+			testEqual(t, "SIMD Decode(%q) = %q, want %q", want, string(dbuf[0:m]), string(src[0:n]))
+		}
+	}
+}
+
+func TestSIMDCorrupt(t *testing.T) {
+	src := make([]byte, 60)
+	rand.New(rand.NewSource(1556)).Read(src)
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		fast := StdEncoding.WithBitOrder(order)
+		slow := *fast
+		slow.swar = false
+		encoded := []byte(fast.EncodeToString(src))
+		for i := range encoded {
+			for _, b := range []byte{'8', '/', '0' ^ 0x80} {
+				corrupt := append([]byte(nil), encoded...)
+				corrupt[i] = b
+
+				want := make([]byte, len(src))
+				wn, werr := slow.Decode(want, corrupt)
+				got := make([]byte, len(src))
+				gn, gerr := fast.Decode(got, corrupt)
+				testEqual(t, "SIMD Decode(%q) = error %v, want %v", corrupt, gerr, werr)
+				testEqual(t, "SIMD Decode(%q) = %q, want %q", corrupt, string(got[0:gn]), string(want[0:wn]))
+			}
+		}
+	}
+}
+
+// TestSIMDWritesAtMostN checks that decoding writes nothing to dst past the
+// decoded bytes, whatever the alignment of the end of the output.
+func TestSIMDWritesAtMostN(t *testing.T) {
+	src := make([]byte, 150)
+	rand.New(rand.NewSource(1556)).Read(src)
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		enc := StdEncoding.WithBitOrder(order)
+		for n := 0; n <= len(src); n++ {
+			encoded := enc.EncodeToString(src[0:n])
+			dbuf := make([]byte, n+16)
+			for i := range dbuf {
+				dbuf[i] = 0xa5
+			}
+			m, err := enc.Decode(dbuf, []byte(encoded))
+			testEqual(t, "Decode(%q) = error %v, want %v", encoded, err, error(nil))
+			testEqual(t, "Decode(%q) = %q, want %q", encoded, string(dbuf[0:m]), string(src[0:n]))
+			for i := m; i < len(dbuf); i++ {
+				if dbuf[i] != 0xa5 {
+					t.Fatalf("Decode(%d bytes) wrote dst[%d] past n = %d", len(encoded), i, m)
+				}
+			}
+		}
+	}
+}
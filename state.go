@@ -0,0 +1,155 @@
+package base8
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errInvalidState is returned by UnmarshalBinary for malformed state.
+var errInvalidState = errors.New("base8: invalid stream state")
+
+// stateVersion is the version of the stream state format written by
+// MarshalBinary.
+const stateVersion = 1
+
+// Tags that distinguish encoder state from decoder state.
+const (
+	encoderStateTag = 'E'
+	decoderStateTag = 'D'
+)
+
+// stateReader reads the fields of a marshaled stream state, remembering
+// whether any of them was malformed.
+type stateReader struct {
+	b   []byte
+	bad bool
+}
+
+func (r *stateReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.bad = true
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *stateReader) field(limit int) []byte {
+	n := r.uvarint()
+	if n > uint64(limit) || n > uint64(len(r.b)) {
+		r.bad = true
+		return nil
+	}
+	b := r.b[0:n]
+	r.b = r.b[n:]
+	return b
+}
+
+// MarshalBinary returns the encoder's state: the bytes written to it that
+// have not yet been encoded because they do not make up a whole block. It
+// implements encoding.BinaryMarshaler, so that a long-running encode can be
+// checkpointed and later resumed, possibly in another process, by an encoder
+// for the same Encoding whose UnmarshalBinary is given the state. Encoded
+// output is not part of the state; it has already been written to the
+// underlying writer. If the encoder has failed, MarshalBinary returns its
+// error.
+func (e *encoder) MarshalBinary() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	b := []byte{stateVersion, encoderStateTag}
+	b = binary.AppendUvarint(b, uint64(e.nbuf))
+	return append(b, e.buf[0:e.nbuf]...), nil
+}
+
+// UnmarshalBinary restores state returned by MarshalBinary, discarding any
+// data buffered by the encoder and any error. The encoder continues to write
+// to its current writer. It implements encoding.BinaryUnmarshaler.
+func (e *encoder) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != stateVersion || data[1] != encoderStateTag {
+		return errInvalidState
+	}
+	r := stateReader{b: data[2:]}
+	buf := r.field(len(e.buf) - 1)
+	if r.bad || len(r.b) != 0 {
+		return errInvalidState
+	}
+	e.err = nil
+	e.nbuf = copy(e.buf[0:], buf)
+	return nil
+}
+
+// MarshalBinary returns the decoder's state: the input read from its
+// underlying reader that has not yet been decoded, the decoded output not
+// yet returned, and the stream offset used to report corrupt input. It
+// implements encoding.BinaryMarshaler, so that a long-running decode can be
+// checkpointed and later resumed, possibly in another process, by a decoder
+// for the same Encoding whose UnmarshalBinary is given the state and whose
+// reader continues where this decoder's reader left off. If the decoder has
+// failed or reached the end of its input, MarshalBinary returns its error.
+func (d *decoder) MarshalBinary() ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	b := []byte{stateVersion, decoderStateTag}
+	end := uint64(0)
+	if d.end {
+		end = 1
+	}
+	b = binary.AppendUvarint(b, end)
+	b = binary.AppendUvarint(b, uint64(d.offset))
+	b = binary.AppendUvarint(b, uint64(d.nbuf))
+	b = append(b, d.buf[0:d.nbuf]...)
+	b = binary.AppendUvarint(b, uint64(len(d.out)))
+	b = append(b, d.out...)
+	if d.ign != nil {
+		// The positions of ignored characters are needed to report offsets
+		// into the original input.
+		b = binary.AppendUvarint(b, uint64(d.ign.kept))
+		b = binary.AppendUvarint(b, uint64(d.ign.base))
+		b = binary.AppendUvarint(b, uint64(len(d.ign.runs)))
+		for _, run := range d.ign.runs {
+			b = binary.AppendUvarint(b, uint64(run.at))
+			b = binary.AppendUvarint(b, uint64(run.n))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary restores state returned by MarshalBinary, discarding any
+// data buffered by the decoder and any error. The decoder continues to read
+// from its current reader. It implements encoding.BinaryUnmarshaler.
+func (d *decoder) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != stateVersion || data[1] != decoderStateTag {
+		return errInvalidState
+	}
+	d.acquire()
+	r := stateReader{b: data[2:]}
+	end := r.uvarint()
+	offset := r.uvarint()
+	buf := r.field(7)
+	out := r.field(len(d.outbuf))
+	var ign ignoringReader
+	if d.ign != nil {
+		ign = ignoringReader{enc: d.enc, r: d.ign.r, runs: d.ign.runs[:0]}
+		ign.kept = int64(r.uvarint())
+		ign.base = int64(r.uvarint())
+		for n := r.uvarint(); n > 0 && !r.bad; n-- {
+			ign.runs = append(ign.runs, ignoredRun{at: int64(r.uvarint()), n: int64(r.uvarint())})
+		}
+	}
+	if r.bad || len(r.b) != 0 || end > 1 || offset > 1<<62 {
+		return errInvalidState
+	}
+
+	d.err = nil
+	d.end = end == 1
+	d.offset = int64(offset)
+	d.nbuf = copy(d.buf[0:], buf)
+	d.out = d.outbuf[0:copy(d.outbuf[0:], out)]
+	if d.ign != nil {
+		*d.ign = ign
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package base8
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEncoderState(t *testing.T) {
+	input := []byte(bigtest.decoded)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		want := enc.EncodeToString(input)
+		for split := 0; split <= len(input); split++ {
+			// Write the first part, checkpoint, and resume in a new encoder.
+			var buf bytes.Buffer
+			w := enc.NewEncoder(&buf)
+			w.Write(input[0:split])
+			state, err := w.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary after %d bytes = %v", split, err)
+			}
+
+			w = enc.NewEncoder(&buf)
+			if err := w.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatalf("UnmarshalBinary after %d bytes = %v", split, err)
+			}
+			w.Write(input[split:])
+			w.Close()
+			testEqual(t, "Resumed encoding split at %d = %q, want %q", split, buf.String(), want)
+		}
+	}
+}
+
+func TestDecoderState(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, StdEncoding.WithIgnoredChars(" ")} {
+		encoded := enc.EncodeToString([]byte(bigtest.decoded))
+		if enc.ignores {
+			encoded = strings.ReplaceAll(encoded, "5", " 5")
+		}
+		for _, size := range []int{8, 24} {
+			for read := 0; read <= len(bigtest.decoded); read++ {
+				// Read part of the output, checkpoint, and resume in a new
+				// decoder that reads the rest of the input.
+				r := strings.NewReader(encoded)
+				d := enc.NewDecoderSize(r, size)
+				p := make([]byte, read)
+				n, _ := io.ReadFull(d, p)
+				state, err := d.(encoding.BinaryMarshaler).MarshalBinary()
+				if err != nil {
+					// The decoder may have reached the end of its input.
+					continue
+				}
+
+				d = enc.NewDecoderSize(strings.NewReader(encoded[len(encoded)-r.Len():]), size)
+				if err := d.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+					t.Fatalf("UnmarshalBinary after reading %d bytes = %v", read, err)
+				}
+				rest, err := io.ReadAll(d)
+				got := string(p[0:n]) + string(rest)
+				testEqual(t, "Resumed decoding after reading %d bytes = error %v, want %v", read, err, error(nil))
+				testEqual(t, "Resumed decoding after reading %d bytes = %q, want %q", read, got, bigtest.decoded)
+			}
+		}
+	}
+
+	// Offsets of corrupt input are counted from the start of the whole
+	// stream.
+	d := NewDecoderSize(strings.NewReader("314675573x460562"), 8)
+	io.ReadFull(d, make([]byte, 3))
+	state, _ := d.(encoding.BinaryMarshaler).MarshalBinary()
+	d = NewDecoder(strings.NewReader("3x460562"))
+	d.(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+	_, err := io.ReadAll(d)
+	testEqual(t, "Resumed decoding corrupt at %d, want %d", corruptOffset(err), int64(9))
+}
+
+func TestInvalidState(t *testing.T) {
+	e := NewEncoder(io.Discard).(encoding.BinaryUnmarshaler)
+	d := NewDecoder(strings.NewReader("")).(encoding.BinaryUnmarshaler)
+	for _, state := range []string{"", "\x01", "\x02E\x00", "\x01D\x00", "\x01E\x03abc", "\x01E\x01", "\x01E\x00x", "\x01E\x00\x00\x00\x00\x00"} {
+		if err := e.UnmarshalBinary([]byte(state)); err != errInvalidState {
+			t.Errorf("encoder UnmarshalBinary(%q) = %v, want %v", state, err, errInvalidState)
+		}
+	}
+	for _, state := range []string{"", "\x01E\x00", "\x01D\x02\x00\x00\x00", "\x01D\x00\x00\x08abcdefgh\x00", "\x01D\x00\x00\x00"} {
+		if err := d.UnmarshalBinary([]byte(state)); err != errInvalidState {
+			t.Errorf("decoder UnmarshalBinary(%q) = %v, want %v", state, err, errInvalidState)
+		}
+	}
+}
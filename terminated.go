@@ -0,0 +1,89 @@
+package base8
+
+import (
+	"bufio"
+	"io"
+)
+
+type terminatedDecoder struct {
+	err    error
+	r      io.ByteReader
+	term   byte
+	offset int64  // offset of the next input byte
+	end    bool   // saw the terminator
+	out    []byte // leftover decoded output
+	outbuf [3]byte
+}
+
+// next reads and decodes the next quantum, which may be cut short by the
+// terminator.
+func (d *terminatedDecoder) next() error {
+	if d.end {
+		return io.EOF
+	}
+
+	var dbuf [8]byte
+	j := 0
+	for ; j < 8; j++ {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		d.offset++
+		if c == d.term {
+			d.end = true
+			break
+		}
+		dbuf[j] = c - '0'
+		if dbuf[j] > 7 {
+			return StdEncoding.invalidSymbol(0, c).shift(d.offset - 1)
+		}
+	}
+
+	// Pack 8x 3-bit source blocks into 3 byte destination quantum, keeping
+	// only the bytes whose bits are all present.
+	d.outbuf[0] = dbuf[0]<<5 | dbuf[1]<<2 | dbuf[2]>>1
+	d.outbuf[1] = dbuf[2]<<7 | dbuf[3]<<4 | dbuf[4]<<1 | dbuf[5]>>2
+	d.outbuf[2] = dbuf[5]<<6 | dbuf[6]<<3 | dbuf[7]
+	d.out = d.outbuf[0 : j*3/8]
+	return nil
+}
+
+func (d *terminatedDecoder) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(d.out) > 0 {
+			nn := copy(p[n:], d.out)
+			d.out = d.out[nn:]
+			n += nn
+			continue
+		}
+		if d.err != nil {
+			break
+		}
+		d.err = d.next()
+	}
+	if n == len(p) {
+		return n, nil
+	}
+	return n, d.err
+}
+
+// NewTerminatedDecoder constructs a new stream decoder for unpadded base8
+// data that is ended by a single terminator byte, term, rather than by
+// padding. The decoder returns io.EOF after reading term; the final quantum,
+// which may be incomplete, is decoded to as many whole bytes as its digits
+// hold and any remaining bits are ignored. If the input ends before term is
+// read, the decoder returns io.ErrUnexpectedEOF. term must not be a digit.
+//
+// If r implements io.ByteReader, the decoder reads no data from r beyond
+// term, so that r may be used to read whatever follows.
+func NewTerminatedDecoder(r io.Reader, term byte) io.Reader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &terminatedDecoder{r: br, term: term}
+}
@@ -0,0 +1,60 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestTerminatedDecoder(t *testing.T) {
+	testCases := []struct {
+		input, want string
+	}{
+		{".", ""},
+		{"3.", ""},
+		{"31.", ""},
+		{"314.", "f"},
+		{"31467.", "f"},
+		{"314674.", "fo"},
+		{"3146755.", "fo"},
+		{"31467557.", "foo"},
+		{"31467557304.", "foob"},
+		{"3146755730460562.rest", "foobar"},
+	}
+	for _, tc := range testCases {
+		r := strings.NewReader(tc.input)
+		decoded, err := ioutil.ReadAll(NewTerminatedDecoder(r, '.'))
+		if err != nil {
+			t.Errorf("NewTerminatedDecoder(%q) failed: %v", tc.input, err)
+		}
+		testEqual(t, "NewTerminatedDecoder(%q) = %q, want %q", tc.input, string(decoded), tc.want)
+
+		// The decoder must not read past the terminator.
+		rest, _ := ioutil.ReadAll(r)
+		want := tc.input[strings.IndexByte(tc.input, '.')+1:]
+		testEqual(t, "NewTerminatedDecoder(%q) left %q, want %q", tc.input, string(rest), want)
+	}
+}
+
+func TestTerminatedDecoderErrors(t *testing.T) {
+	encoded := strings.TrimRight(bigtest.encoded, "=") + "="
+	decoded, err := ioutil.ReadAll(NewTerminatedDecoder(strings.NewReader(encoded), '='))
+	if err != nil {
+		t.Errorf("NewTerminatedDecoder(%q) failed: %v", encoded, err)
+	}
+	testEqual(t, "NewTerminatedDecoder(%q) = %q, want %q", encoded, string(decoded), bigtest.decoded)
+
+	_, err = ioutil.ReadAll(NewTerminatedDecoder(strings.NewReader("31467557304"), '.'))
+	testEqual(t, "Unterminated input error = %v, want %v", err, io.ErrUnexpectedEOF)
+
+	_, err = ioutil.ReadAll(NewTerminatedDecoder(strings.NewReader("3146755730x."), '.'))
+	testEqual(t, "Corrupt input error = %v, want %v", corruptOffset(err), int64(10))
+
+	_, err = ioutil.ReadAll(NewTerminatedDecoder(strings.NewReader("3146755730460562===."), '.'))
+	testEqual(t, "Padded input error = %v, want %v", corruptOffset(err), int64(16))
+
+	_, err = ioutil.ReadAll(NewTerminatedDecoder(bytes.NewReader(nil), '.'))
+	testEqual(t, "Empty input error = %v, want %v", err, io.ErrUnexpectedEOF)
+}
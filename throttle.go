@@ -0,0 +1,67 @@
+package base8
+
+import (
+	"io"
+	"time"
+)
+
+type throttledWriter struct {
+	w       io.Writer
+	rate    int   // bytes per second
+	written int64 // bytes written since start
+	start   time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func (t *throttledWriter) Write(p []byte) (n int, err error) {
+	if t.start.IsZero() {
+		t.start = t.now()
+	}
+
+	// Write in slices of at most 1/8s worth of output so that the rate
+	// holds over short intervals as well as long ones.
+	chunk := t.rate / 8
+	if chunk < 1 {
+		chunk = 1
+	}
+	for len(p) > 0 {
+		// Wait until the bytes written so far are within budget.
+		// The whole seconds and the remainder are scaled separately, so
+		// that the product does not overflow after about 9.2GB.
+		rate := int64(t.rate)
+		budget := time.Duration(t.written/rate)*time.Second +
+			time.Duration(t.written%rate)*time.Second/time.Duration(rate)
+		due := t.start.Add(budget)
+		if d := due.Sub(t.now()); d > 0 {
+			t.sleep(d)
+		}
+
+		nn := chunk
+		if nn > len(p) {
+			nn = len(p)
+		}
+		nn, err = t.w.Write(p[0:nn])
+		n += nn
+		t.written += int64(nn)
+		if err != nil {
+			return n, err
+		}
+		p = p[nn:]
+	}
+	return n, nil
+}
+
+// NewThrottledEncoder returns a new base8 stream encoder that limits the rate
+// at which encoded data is written to w to approximately bytesPerSec bytes per
+// second, sleeping between writes as necessary. As with NewEncoder, the caller
+// must Close the returned encoder to flush any partially written blocks.
+// bytesPerSec must be positive.
+func NewThrottledEncoder(w io.Writer, bytesPerSec int) io.WriteCloser {
+	return newThrottledEncoder(w, bytesPerSec, time.Now, time.Sleep)
+}
+
+func newThrottledEncoder(w io.Writer, bytesPerSec int, now func() time.Time, sleep func(time.Duration)) io.WriteCloser {
+	return NewEncoder(&throttledWriter{w: w, rate: bytesPerSec, now: now, sleep: sleep})
+}
@@ -0,0 +1,89 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) sleep(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// timedWriter records the fake time at which each byte was written.
+type timedWriter struct {
+	clock *fakeClock
+	buf   bytes.Buffer
+	times []time.Time
+}
+
+func (w *timedWriter) Write(p []byte) (int, error) {
+	for range p {
+		w.times = append(w.times, w.clock.t)
+	}
+	return w.buf.Write(p)
+}
+
+func TestThrottledEncoder(t *testing.T) {
+	const rate = 100
+
+	raw := bytes.Repeat([]byte(bigtest.decoded), 40)
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	sink := &timedWriter{clock: clock}
+	start := clock.t
+
+	w := newThrottledEncoder(sink, rate, clock.now, clock.sleep)
+	for pos := 0; pos < len(raw); pos += 100 {
+		end := pos + 100
+		if end > len(raw) {
+			end = len(raw)
+		}
+		if _, err := w.Write(raw[pos:end]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	testEqual(t, "Throttled encoding = %q, want %q", sink.buf.String(), EncodeToString(raw))
+
+	// By time t, no more than rate*t bytes plus one slice may have been
+	// written.
+	for i, at := range sink.times {
+		elapsed := at.Sub(start).Seconds()
+		if limit := rate*elapsed + rate/8; float64(i) > limit {
+			t.Fatalf("byte %d written after %vs, exceeding rate %d", i, elapsed, rate)
+		}
+	}
+
+	// The total time should be close to the ideal.
+	elapsed := clock.t.Sub(start).Seconds()
+	ideal := float64(len(sink.times)) / rate
+	if elapsed < ideal-1 || elapsed > ideal+1 {
+		t.Errorf("encoding %d bytes took %vs, want about %vs", len(sink.times), elapsed, ideal)
+	}
+}
+
+func TestThrottledWriterLargeTotal(t *testing.T) {
+	const rate = 1 << 20
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	start := clock.t
+
+	// 16GiB have been written, which takes 16384s at rate; 1s remains.
+	tw := &throttledWriter{w: io.Discard, rate: rate, written: 16 << 30, start: start, now: clock.now, sleep: clock.sleep}
+	clock.t = start.Add(16383 * time.Second)
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	testEqual(t, "Write after 16GiB finished at %v, want %v", clock.t, start.Add(16384*time.Second))
+}
@@ -0,0 +1,40 @@
+package base8
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errTimeLength = errors.New("base8: encoded time must decode to 8 bytes")
+
+// EncodeTime returns the base8 encoding of the 8-byte big-endian
+// representation of t.UnixNano(). Only the zero Time and times whose
+// nanosecond Unix time fits in an int64 (roughly the years 1678 through 2262)
+// can be represented. The zero Time, which lies outside that range, is
+// encoded as the empty string, a length that no other time's encoding has.
+func EncodeTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[0:], uint64(t.UnixNano()))
+	return EncodeToString(b[0:])
+}
+
+// DecodeTime returns the time represented by the base8 string s, which must
+// have been produced by EncodeTime. The result is the zero Time if s is
+// empty, and otherwise a time in UTC.
+func DecodeTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	b, err := DecodeString(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(b) != 8 {
+		return time.Time{}, errTimeLength
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b))).UTC(), nil
+}
@@ -0,0 +1,57 @@
+package base8
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	times := []time.Time{
+		time.Unix(0, 0),
+		time.Unix(0, 1),
+		time.Unix(-1, 0),
+		time.Date(2020, time.November, 3, 12, 34, 56, 789012345, time.UTC),
+		time.Date(2262, time.April, 11, 23, 47, 16, 854775807, time.UTC),
+		time.Date(1677, time.September, 21, 0, 12, 43, 145224192, time.UTC),
+		time.Date(2001, time.February, 3, 4, 5, 6, 7, time.FixedZone("x", -7*60*60)),
+	}
+	for _, tm := range times {
+		s := EncodeTime(tm)
+		if len(s) != EncodedLen(8) {
+			t.Errorf("EncodeTime(%v) = %q, want length %d", tm, s, EncodedLen(8))
+		}
+		got, err := DecodeTime(s)
+		if err != nil {
+			t.Errorf("DecodeTime(%q) failed: %v", s, err)
+			continue
+		}
+		if !got.Equal(tm) || got.Nanosecond() != tm.Nanosecond() {
+			t.Errorf("DecodeTime(EncodeTime(%v)) = %v", tm, got)
+		}
+		if got.Location() != time.UTC {
+			t.Errorf("DecodeTime(%q) location = %v, want UTC", s, got.Location())
+		}
+	}
+}
+
+func TestZeroTime(t *testing.T) {
+	s := EncodeTime(time.Time{})
+	got, err := DecodeTime(s)
+	if err != nil {
+		t.Fatalf("DecodeTime(%q) failed: %v", s, err)
+	}
+	if !got.IsZero() || got != (time.Time{}) {
+		t.Errorf("DecodeTime(EncodeTime(time.Time{})) = %v, want the zero Time", got)
+	}
+	if s != "" {
+		t.Errorf("EncodeTime(time.Time{}) = %q, want %q", s, "")
+	}
+}
+
+func TestDecodeTimeErrors(t *testing.T) {
+	for _, s := range []string{"314=====", EncodeToString(make([]byte, 9)), "0000000!"} {
+		if _, err := DecodeTime(s); err == nil {
+			t.Errorf("DecodeTime(%q) succeeded, want error", s)
+		}
+	}
+}
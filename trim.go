@@ -0,0 +1,50 @@
+package base8
+
+import "strings"
+
+/*
+ * Integer interpretation
+ *
+ * Because each digit carries exactly 3 bits and digits are packed
+ * most-significant-bit first, a sequence of complete quanta is also the
+ * octal numeral of the big-endian unsigned integer represented by the
+ * decoded bytes. The functions below operate on that interpretation rather
+ * than on the block codec: they discard leading zero digits, and with them
+ * any leading zero bytes of the decoded data, while preserving the integer
+ * value. They do not accept padded input.
+ */
+
+// TrimLeadingZeros returns s with its leading '0' digits removed, keeping at
+// least one digit. s should consist of complete quanta; the result is the
+// minimal octal numeral for the integer that s represents.
+func TrimLeadingZeros(s string) string {
+	t := strings.TrimLeft(s, "0")
+	if t == "" && s != "" {
+		return "0"
+	}
+	return t
+}
+
+// DecodeTrimmed decodes s, which may have had its leading zero digits removed
+// by TrimLeadingZeros, by left-padding it with '0' digits to the next quantum
+// boundary. The result represents the same integer as the untrimmed input,
+// but omits any leading zero bytes that padding does not restore. Padded
+// input is rejected with a CorruptInputError that wraps ErrInvalidPadding.
+func DecodeTrimmed(s string) ([]byte, error) {
+	if i := strings.IndexByte(s, PadChar); i >= 0 {
+		return nil, StdEncoding.invalidSymbol(i, PadChar)
+	}
+
+	fill := (8 - len(s)%8) % 8
+	buf := make([]byte, fill+len(s))
+	for i := 0; i < fill; i++ {
+		buf[i] = '0'
+	}
+	copy(buf[fill:], s)
+
+	n, _, err := StdEncoding.decode(buf, buf)
+	if err != nil {
+		err = err.(CorruptInputError).shift(int64(-fill))
+	}
+	return buf[:n], err
+}
@@ -0,0 +1,70 @@
+package base8
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestTrimLeadingZeros(t *testing.T) {
+	testCases := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"00000000", "0"},
+		{"00000001", "1"},
+		{"31467557", "31467557"},
+		{"0000000031467557", "31467557"},
+		{"00314675", "314675"},
+	}
+	for _, tc := range testCases {
+		testEqual(t, "TrimLeadingZeros(%q) = %q, want %q", tc.input, TrimLeadingZeros(tc.input), tc.want)
+	}
+}
+
+func TestDecodeTrimmed(t *testing.T) {
+	inputs := [][]byte{
+		{0, 0, 0},
+		{0, 0, 1},
+		{0, 0, 0, 0, 0, 7, 1, 2, 3},
+		[]byte("foobar"),
+		{0, 0xff, 0xff},
+	}
+	for _, in := range inputs {
+		want := new(big.Int).SetBytes(in)
+
+		trimmed := TrimLeadingZeros(EncodeToString(in))
+		octal, ok := new(big.Int).SetString(trimmed, 8)
+		if !ok || octal.Cmp(want) != 0 {
+			t.Errorf("TrimLeadingZeros(Encode(%x)) = %q, want octal %s", in, trimmed, want.Text(8))
+		}
+
+		decoded, err := DecodeTrimmed(trimmed)
+		if err != nil {
+			t.Errorf("DecodeTrimmed(%q) failed: %v", trimmed, err)
+			continue
+		}
+		if got := new(big.Int).SetBytes(decoded); got.Cmp(want) != 0 {
+			t.Errorf("DecodeTrimmed(%q) = %x, want value %x", trimmed, decoded, want)
+		}
+		if len(decoded)%3 != 0 {
+			t.Errorf("DecodeTrimmed(%q) = %x, want whole quanta", trimmed, decoded)
+		}
+	}
+
+	_, err := DecodeTrimmed("31468")
+	testEqual(t, "DecodeTrimmed(%q) = %v, want %v", "31468", corruptOffset(err), int64(4))
+
+	for _, tc := range []struct {
+		input string
+		off   int64
+	}{
+		{"314=====", 3},
+		{"31467===", 5},
+		{"0000000031467===", 13},
+		{"=", 0},
+	} {
+		_, err := DecodeTrimmed(tc.input)
+		want := CorruptInputError{offset: tc.off, b: '=', expected: ExpectSymbol, err: ErrInvalidPadding}
+		testEqual(t, "DecodeTrimmed(%q) = %#v, want %#v", tc.input, err, error(want))
+	}
+}
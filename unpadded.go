@@ -0,0 +1,105 @@
+package base8
+
+import "errors"
+
+// unpadded returns enc if it uses NoPadding, and otherwise a copy of enc that
+// uses NoPadding, for decoding input whose final quantum is not padded.
+func (enc *Encoding) unpadded() *Encoding {
+	if enc.padChar == NoPadding {
+		return enc
+	}
+	raw := *enc
+	raw.decodeMap[raw.padChar] = invalidIndex
+	raw.padChar = NoPadding
+	raw.escape = false
+	return &raw
+}
+
+// decodeUnpadded decodes s, which must not be padded, with raw, an encoding
+// returned by unpadded. The final quantum of s may hold 3 or 6 digits, which
+// decode to 1 or 2 bytes respectively; a final quantum of any other length
+// is reported as truncated at the end of s.
+func decodeUnpadded(raw *Encoding, s string) ([]byte, error) {
+	b, err := raw.DecodeString(s)
+	if errors.Is(err, ErrInvalidLength) {
+		err = truncated(len(s), ExpectSymbol)
+	}
+	return b, err
+}
+
+// DecodeExact decodes exactly decodedLen bytes from the base8 string s, for
+// protocols that carry the decoded length out of band and omit the padding of
+// the final quantum. s may be padded or unpadded; it must contain exactly the
+// digits required to represent decodedLen bytes, followed by nothing or, if
+// enc uses padding, by the padding that completes the final quantum. Unused
+// bits in the final digit are ignored.
+func (enc *Encoding) DecodeExact(s string, decodedLen int) ([]byte, error) {
+	need := EncodedLenNoPad(decodedLen)
+	if len(s) < need {
+		return nil, truncated(len(s), ExpectSymbol)
+	}
+	if len(s) > need {
+		// The remainder must be exactly the padding of the final quantum.
+		padded := (need + 7) / 8 * 8
+		for i := need; i < len(s); i++ {
+			if i >= padded || rune(s[i]) != enc.padChar {
+				return nil, invalidPadding(i, s[i])
+			}
+		}
+		if len(s) != padded {
+			return nil, truncated(len(s), ExpectPadding)
+		}
+	}
+	return decodeUnpadded(enc.unpadded(), s[:need])
+}
+
+// DecodeExact decodes exactly decodedLen bytes from the standard base8 string
+// s, which may be padded or unpadded.
+func DecodeExact(s string, decodedLen int) ([]byte, error) {
+	return StdEncoding.DecodeExact(s, decodedLen)
+}
+
+// DecodeFixedRecords decodes s as a concatenation of unpadded base8 records,
+// each encoding exactly recordDecodedLen bytes, and returns the decoded
+// records. len(s) must be a multiple of the encoded record length.
+// recordDecodedLen must be positive.
+func (enc *Encoding) DecodeFixedRecords(s string, recordDecodedLen int) ([][]byte, error) {
+	size := EncodedLenNoPad(recordDecodedLen)
+	if len(s)%size != 0 {
+		return nil, truncated(len(s)-len(s)%size, ExpectSymbol)
+	}
+
+	raw := enc.unpadded()
+	records := make([][]byte, 0, len(s)/size)
+	for off := 0; off < len(s); off += size {
+		record, err := decodeUnpadded(raw, s[off:off+size])
+		if err != nil {
+			return records, err.(CorruptInputError).shift(int64(off))
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// DecodeFixedRecords decodes s as a concatenation of unpadded standard base8
+// records, each encoding exactly recordDecodedLen bytes.
+func DecodeFixedRecords(s string, recordDecodedLen int) ([][]byte, error) {
+	return StdEncoding.DecodeFixedRecords(s, recordDecodedLen)
+}
+
+// DecodeStringAuto returns the bytes represented by the base8 string s, which
+// may be padded or unpadded. Input whose length is a multiple of 8 and that
+// ends in enc's padding character is decoded as padded base8; any other input
+// is decoded as unpadded base8. Malformed input is rejected in either case.
+func (enc *Encoding) DecodeStringAuto(s string) ([]byte, error) {
+	if len(s)%8 == 0 && len(s) > 0 && rune(s[len(s)-1]) == enc.padChar {
+		return enc.DecodeString(s)
+	}
+	return decodeUnpadded(enc.unpadded(), s)
+}
+
+// DecodeStringAuto returns the bytes represented by the standard base8 string
+// s, which may be padded or unpadded.
+func DecodeStringAuto(s string) ([]byte, error) {
+	return StdEncoding.DecodeStringAuto(s)
+}
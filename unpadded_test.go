@@ -0,0 +1,147 @@
+package base8
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeExact(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		for _, encoded := range []string{p.encoded, strings.TrimRight(p.encoded, "=")} {
+			got, err := DecodeExact(encoded, len(p.decoded))
+			if err != nil {
+				t.Errorf("DecodeExact(%q, %d) failed: %v", encoded, len(p.decoded), err)
+				continue
+			}
+			testEqual(t, "DecodeExact(%q, %d) = %q, want %q", encoded, len(p.decoded), string(got), p.decoded)
+		}
+	}
+
+	testCases := []struct {
+		input      string
+		decodedLen int
+		want       string
+	}{
+		{"314", 1, "f"},
+		{"315", 1, "f"},
+		{"314674", 2, "fo"},
+		{"31467557314", 4, "foof"},
+		{"31467557314674", 5, "foofo"},
+	}
+	for _, tc := range testCases {
+		got, err := DecodeExact(tc.input, tc.decodedLen)
+		if err != nil {
+			t.Errorf("DecodeExact(%q, %d) failed: %v", tc.input, tc.decodedLen, err)
+			continue
+		}
+		testEqual(t, "DecodeExact(%q, %d) = %q, want %q", tc.input, tc.decodedLen, string(got), tc.want)
+	}
+}
+
+func TestDecodeExactCorrupt(t *testing.T) {
+	testCases := []struct {
+		input      string
+		decodedLen int
+		offset     int
+	}{
+		{"31", 1, 2},
+		{"314", 2, 3},
+		{"31467557", 4, 8},
+		{"3146", 1, 3},
+		{"314====", 1, 7},
+		{"314======", 1, 8},
+		{"314==4==", 1, 5},
+		{"31!", 1, 2},
+		{"31467!57", 3, 5},
+	}
+	for _, tc := range testCases {
+		_, err := DecodeExact(tc.input, tc.decodedLen)
+		testEqual(t, "DecodeExact(%q, %d) = %v, want %v", tc.input, tc.decodedLen, corruptOffset(err), int64(tc.offset))
+	}
+}
+
+func TestDecodeFixedRecords(t *testing.T) {
+	raw := []string{"foob", "\x00\x01\x02\x03", "\xff\xfe\xfd\xfc"}
+	var s string
+	for _, r := range raw {
+		s += strings.TrimRight(EncodeToString([]byte(r)), "=")
+	}
+	testEqual(t, "encoded records length = %v, want %v", len(s), 33)
+
+	records, err := DecodeFixedRecords(s, 4)
+	if err != nil {
+		t.Fatalf("DecodeFixedRecords(%q, 4) failed: %v", s, err)
+	}
+	testEqual(t, "DecodeFixedRecords(%q, 4) = %v records, want %v", s, len(records), len(raw))
+	for i, r := range records {
+		testEqual(t, "DecodeFixedRecords record %d = %q, want %q", i, string(r), raw[i])
+	}
+
+	_, err = DecodeFixedRecords(s[:32], 4)
+	testEqual(t, "DecodeFixedRecords(truncated) = %v, want %v", corruptOffset(err), int64(22))
+
+	_, err = DecodeFixedRecords(s[:15]+"9"+s[16:], 4)
+	testEqual(t, "DecodeFixedRecords(corrupt) = %v, want %v", corruptOffset(err), int64(15))
+}
+
+func TestDecodeStringAuto(t *testing.T) {
+	for _, p := range append(pairs, bigtest) {
+		for _, encoded := range []string{p.encoded, strings.TrimRight(p.encoded, "=")} {
+			got, err := DecodeStringAuto(encoded)
+			if err != nil {
+				t.Errorf("DecodeStringAuto(%q) failed: %v", encoded, err)
+				continue
+			}
+			testEqual(t, "DecodeStringAuto(%q) = %q, want %q", encoded, string(got), p.decoded)
+		}
+	}
+
+	for _, tc := range []struct {
+		input  string
+		offset int
+	}{
+		{"3", 1},
+		{"3146", 4},
+		{"314==", 3},
+		{"314=====31467557", 3},
+		{"3146755=", 7},
+		{"31467557!", 8},
+		{"1111====", 4},
+	} {
+		_, err := DecodeStringAuto(tc.input)
+		testEqual(t, "DecodeStringAuto(%q) = %v, want %v", tc.input, corruptOffset(err), int64(tc.offset))
+	}
+}
+
+func TestUnpaddedEncodings(t *testing.T) {
+	for _, enc := range []*Encoding{LetterEncoding, StdEncoding.WithBitOrder(LSBFirst), StdEncoding.WithPadding('*'), RawEncoding} {
+		for _, p := range append(pairs, bigtest) {
+			padded := enc.EncodeToString([]byte(p.decoded))
+			for _, encoded := range []string{padded, strings.TrimRight(padded, string(enc.padChar))} {
+				got, err := enc.DecodeExact(encoded, len(p.decoded))
+				testEqual(t, "DecodeExact(%q, %d) = error %v, want %v", encoded, len(p.decoded), err, error(nil))
+				testEqual(t, "DecodeExact(%q, %d) = %q, want %q", encoded, len(p.decoded), string(got), p.decoded)
+
+				got, err = enc.DecodeStringAuto(encoded)
+				testEqual(t, "DecodeStringAuto(%q) = error %v, want %v", encoded, err, error(nil))
+				testEqual(t, "DecodeStringAuto(%q) = %q, want %q", encoded, string(got), p.decoded)
+			}
+		}
+
+		records := []string{"foob", "\x00\x01\x02\x03", "\xff\xfe\xfd\xfc"}
+		var s string
+		for _, r := range records {
+			s += enc.unpadded().EncodeToString([]byte(r))
+		}
+		got, err := enc.DecodeFixedRecords(s, 4)
+		testEqual(t, "DecodeFixedRecords(%q, 4) = error %v, want %v", s, err, error(nil))
+		testEqual(t, "DecodeFixedRecords(%q, 4) = %v records, want %v", s, len(got), len(records))
+		for i := range got {
+			testEqual(t, "DecodeFixedRecords(%q, 4) record %d = %q, want %q", s, i, string(got[i]), records[i])
+		}
+	}
+
+	// The digits of the standard alphabet are not symbols of LetterEncoding.
+	_, err := LetterEncoding.DecodeStringAuto("314")
+	testEqual(t, "LetterEncoding.DecodeStringAuto(%q) = %v, want %v", "314", corruptOffset(err), int64(0))
+}
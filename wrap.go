@@ -0,0 +1,86 @@
+package base8
+
+import "io"
+
+// lineWrapper inserts a line ending after every width bytes written to w.
+type lineWrapper struct {
+	w      io.Writer
+	width  int
+	ending string
+	col    int // number of bytes written to the current line
+}
+
+func (l *lineWrapper) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		nn, err := l.w.Write(p[0:min(len(p), l.width-l.col)])
+		n += nn
+		l.col += nn
+		p = p[nn:]
+		if err != nil {
+			return n, err
+		}
+		if l.col == l.width {
+			if _, err := io.WriteString(l.w, l.ending); err != nil {
+				return n, err
+			}
+			l.col = 0
+		}
+	}
+	return n, nil
+}
+
+type wrappingEncoder struct {
+	io.WriteCloser
+	l *lineWrapper
+}
+
+// Close flushes any pending output from the encoder and terminates the final
+// line.
+func (e *wrappingEncoder) Close() error {
+	if err := e.WriteCloser.Close(); err != nil {
+		return err
+	}
+	if e.l.col > 0 {
+		if _, err := io.WriteString(e.l.w, e.l.ending); err != nil {
+			return err
+		}
+		e.l.col = 0
+	}
+	return nil
+}
+
+// NewWrappingEncoder returns a new base8 stream encoder like NewEncoder, but
+// whose output is broken into lines of width characters, each followed by
+// lineEnding, so that it can be embedded in configuration files and email.
+// Every line, including the last, is terminated by lineEnding. The caller
+// must Close the returned encoder to flush the final block and line. width
+// must be positive.
+//
+// The output can be decoded by NewWrappingDecoder, or by any decoder whose
+// encoding ignores the characters of lineEnding.
+func (enc *Encoding) NewWrappingEncoder(w io.Writer, width int, lineEnding string) io.WriteCloser {
+	if width <= 0 {
+		panic("invalid line width")
+	}
+	l := &lineWrapper{w: w, width: width, ending: lineEnding}
+	return &wrappingEncoder{WriteCloser: enc.NewEncoder(l), l: l}
+}
+
+// NewWrappingEncoder returns a new base8 stream encoder that uses the
+// standard encoding and breaks its output into lines of width characters.
+func NewWrappingEncoder(w io.Writer, width int, lineEnding string) io.WriteCloser {
+	return StdEncoding.NewWrappingEncoder(w, width, lineEnding)
+}
+
+// NewWrappingDecoder constructs a new base8 stream decoder for the output of
+// NewWrappingEncoder. It ignores the characters of lineEnding wherever they
+// appear, so the input may be wrapped at any width.
+func (enc *Encoding) NewWrappingDecoder(r io.Reader, lineEnding string) io.Reader {
+	return enc.WithIgnoredChars(lineEnding).NewDecoder(r)
+}
+
+// NewWrappingDecoder constructs a new base8 stream decoder for the output of
+// NewWrappingEncoder that uses the standard encoding.
+func NewWrappingDecoder(r io.Reader, lineEnding string) io.Reader {
+	return StdEncoding.NewWrappingDecoder(r, lineEnding)
+}
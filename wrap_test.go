@@ -0,0 +1,71 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWrappingEncoder(t *testing.T) {
+	testCases := []struct {
+		decoded    string
+		width      int
+		lineEnding string
+		encoded    string
+	}{
+		{"", 4, "\n", ""},
+		{"f", 4, "\n", "314=\n====\n"},
+		{"f", 8, "\n", "314=====\n"},
+		{"foob", 5, "\r\n", "31467\r\n55730\r\n4====\r\n=\r\n"},
+		{"foobar", 76, "\n", "3146755730460562\n"},
+	}
+	for _, tc := range testCases {
+		bb := &bytes.Buffer{}
+		w := NewWrappingEncoder(bb, tc.width, tc.lineEnding)
+		w.Write([]byte(tc.decoded))
+		err := w.Close()
+		testEqual(t, "Close gave error %v, want %v", err, error(nil))
+		testEqual(t, "NewWrappingEncoder(%q, %d) = %q, want %q", tc.decoded, tc.width, bb.String(), tc.encoded)
+	}
+}
+
+func TestWrappingRoundTrip(t *testing.T) {
+	input := bytes.Repeat([]byte(bigtest.decoded), 50)
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding} {
+		for _, width := range []int{1, 7, 64, 76, 1000} {
+			for _, ending := range []string{"\n", "\r\n"} {
+				bb := &bytes.Buffer{}
+				w := enc.NewWrappingEncoder(bb, width, ending)
+				// Write in odd-sized pieces so that lines span writes.
+				for pos := 0; pos < len(input); pos += 5 {
+					w.Write(input[pos:min(pos+5, len(input))])
+				}
+				w.Close()
+
+				for i, line := range strings.SplitAfter(bb.String(), ending) {
+					if line == "" {
+						continue
+					}
+					if !strings.HasSuffix(line, ending) || len(line) > width+len(ending) {
+						t.Fatalf("width %d: line %d is %q", width, i, line)
+					}
+				}
+				testEqual(t, "width %d: unwrapped output = %q, want %q", width, strings.ReplaceAll(bb.String(), ending, ""), enc.EncodeToString(input))
+
+				got, err := io.ReadAll(enc.NewWrappingDecoder(bb, ending))
+				testEqual(t, "NewWrappingDecoder/%d = error %v, want %v", width, err, error(nil))
+				testEqual(t, "NewWrappingDecoder/%d = %q, want %q", width, string(got), string(input))
+			}
+		}
+	}
+}
+
+func TestWrappingEncoderPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewWrappingEncoder(w, 0, %q) did not panic", "\n")
+		}
+	}()
+	NewWrappingEncoder(io.Discard, 0, "\n")
+}
@@ -0,0 +1,139 @@
+package base8
+
+import (
+	"bytes"
+	"io"
+)
+
+type decodingWriter struct {
+	err    error
+	enc    *Encoding
+	w      io.Writer
+	buf    [8]byte         // partial quantum waiting for more input
+	nbuf   int             // number of bytes in buf
+	end    bool            // saw end of message
+	offset int64           // offset of buf[0] in the input stream
+	in     int64           // number of bytes written so far
+	ign    *ignoringReader // filters the input if enc ignores characters
+	out    []byte          // decoded output buffer
+}
+
+// inputOffset returns the offset in the unfiltered input of the filtered
+// input byte with offset k.
+func (d *decodingWriter) inputOffset(k int64) int64 {
+	if d.ign != nil {
+		return d.ign.offset(k)
+	}
+	return k
+}
+
+// decodeChunk decodes src, which starts at offset d.offset of the filtered
+// input, writes the result to d.w and advances d.offset past src.
+func (d *decodingWriter) decodeChunk(src []byte) error {
+	n, end, err := decodeStripped(d.enc, d.out[0:], src)
+	if err != nil {
+		e := err.(CorruptInputError)
+		return e.shift(d.inputOffset(d.offset+e.offset) - e.offset)
+	}
+	d.end = end
+	if _, err := d.w.Write(d.out[0:n]); err != nil {
+		return err
+	}
+	d.offset += int64(len(src))
+	if d.ign != nil {
+		d.ign.discard(d.offset)
+	}
+	return nil
+}
+
+func (d *decodingWriter) Write(p []byte) (n int, err error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	// consumed returns the number of bytes of p that precede the filtered
+	// input byte with offset k.
+	start := d.in
+	consumed := func(k int64) int {
+		return int(min(max(d.inputOffset(k)-start, 0), int64(len(p))))
+	}
+
+	src := p
+	if d.ign != nil {
+		d.ign.r = bytes.NewReader(p)
+		src, _ = io.ReadAll(d.ign)
+	}
+	d.in += int64(len(p))
+
+	for len(src) > 0 {
+		if d.end {
+			// Nothing may follow the padding that ended the message.
+			off := d.offset + int64(d.nbuf)
+			d.err = invalidPadding(0, src[0]).shift(d.inputOffset(off))
+			return consumed(off), d.err
+		}
+
+		// Leading fringe.
+		if d.nbuf > 0 || len(src) < 8 {
+			m := copy(d.buf[d.nbuf:], src)
+			d.nbuf += m
+			src = src[m:]
+			if d.nbuf < 8 {
+				break
+			}
+			if d.err = d.decodeChunk(d.buf[0:]); d.err != nil {
+				return consumed(d.offset), d.err
+			}
+			d.nbuf = 0
+			continue
+		}
+
+		// Large interior chunks.
+		nn := min(len(src), len(d.out)/3*8)
+		nn -= nn % 8
+		if d.err = d.decodeChunk(src[0:nn]); d.err != nil {
+			return consumed(d.offset), d.err
+		}
+		src = src[nn:]
+	}
+	return len(p), nil
+}
+
+// Close decodes any buffered partial quantum, which is only valid if enc uses
+// NoPadding or has a length escape, and reports an error if the input ended in the middle of a
+// quantum. It does not close the underlying writer.
+func (d *decodingWriter) Close() error {
+	if d.err == nil && d.nbuf > 0 {
+		d.err = d.decodeChunk(d.buf[0:d.nbuf])
+		d.nbuf = 0
+	}
+	return d.err
+}
+
+// NewDecodingWriter returns a writer that decodes the base8 data written to
+// it using enc and writes the decoded bytes to w. It is the push-based
+// counterpart of NewDecoder, for callers that receive encoded data in pieces,
+// such as message handlers, rather than reading it from an io.Reader. Data
+// may be split across writes at any point; a partial quantum is buffered
+// until the rest of it arrives. When finished writing, the caller must Close
+// the returned writer to check that the input ended on a quantum boundary
+// and, if enc uses NoPadding or has a length escape, to flush the final
+// partial quantum.
+//
+// The offset of a CorruptInputError it returns is counted from the start of
+// the stream. Any data written after the padding that ends a message is
+// reported as invalid padding. Once an error has occurred, it is returned by
+// all subsequent calls to Write and Close.
+func (enc *Encoding) NewDecodingWriter(w io.Writer) io.WriteCloser {
+	d := &decodingWriter{enc: enc, w: w, out: make([]byte, defaultBufSize/8*3)}
+	if enc.ignores {
+		d.ign = &ignoringReader{enc: enc}
+	}
+	return d
+}
+
+// NewDecodingWriter returns a writer that decodes the standard base8 data
+// written to it and writes the decoded bytes to w.
+func NewDecodingWriter(w io.Writer) io.WriteCloser {
+	return StdEncoding.NewDecodingWriter(w)
+}
@@ -0,0 +1,84 @@
+package base8
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodingWriter(t *testing.T) {
+	for _, enc := range []*Encoding{StdEncoding, RawEncoding, StdEncoding.WithIgnoredChars("\n"), StdEncoding.WithLengthEscape()} {
+		for _, p := range append(pairs, bigtest) {
+			encoded := enc.EncodeToString([]byte(p.decoded))
+			if enc.ignores {
+				encoded = strings.Join(strings.SplitAfter(encoded, "3"), "\n")
+			}
+			for split := 0; split <= len(encoded); split++ {
+				var buf bytes.Buffer
+				w := enc.NewDecodingWriter(&buf)
+				n1, err1 := w.Write([]byte(encoded[:split]))
+				n2, err2 := w.Write([]byte(encoded[split:]))
+				err := errors.Join(err1, err2, w.Close())
+				testEqual(t, "NewDecodingWriter(%q) split at %d = error %v, want %v", encoded, split, err, error(nil))
+				testEqual(t, "NewDecodingWriter(%q) split at %d wrote %d bytes, want %d", encoded, split, n1+n2, len(encoded))
+				testEqual(t, "NewDecodingWriter(%q) split at %d = %q, want %q", encoded, split, buf.String(), p.decoded)
+			}
+		}
+	}
+
+	big := bytes.Repeat([]byte(bigtest.decoded), 1000)
+	var buf bytes.Buffer
+	w := NewDecodingWriter(&buf)
+	w.Write([]byte(EncodeToString(big)))
+	if err := w.Close(); err != nil {
+		t.Fatalf("NewDecodingWriter(big).Close() = %v, want nil", err)
+	}
+	if !bytes.Equal(buf.Bytes(), big) {
+		t.Errorf("NewDecodingWriter(big) decoded %d bytes, want %d", buf.Len(), len(big))
+	}
+}
+
+func TestDecodingWriterCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		enc    *Encoding
+		writes []string
+		offset int64
+		err    error
+	}{
+		{StdEncoding, []string{"3146", "7557", "3x"}, 9, ErrInvalidCharacter},
+		{StdEncoding, []string{"314=====", "3"}, 8, ErrInvalidPadding},
+		{StdEncoding, []string{"31467557", "314"}, 8, ErrInvalidLength},
+		{RawEncoding, []string{"31467557", "3146"}, 8, ErrInvalidLength},
+		{StdEncoding.WithIgnoredChars(" "), []string{"31 46 75", " 57 3x"}, 13, ErrInvalidCharacter},
+		{StdEncoding.WithLengthEscape(), []string{"31467557=1", "304", "3"}, 13, ErrInvalidPadding},
+		{StdEncoding.WithLengthEscape(), []string{"31467557=23", "14"}, 8, ErrInvalidLength},
+		{StdEncoding.WithLengthEscape(), []string{"31467557=3314"}, 9, ErrInvalidCharacter},
+	} {
+		w := tc.enc.NewDecodingWriter(&bytes.Buffer{})
+		var err error
+		for _, s := range tc.writes {
+			if _, err = w.Write([]byte(s)); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		if !errors.Is(err, tc.err) || corruptOffset(err) != tc.offset {
+			t.Errorf("NewDecodingWriter(%q) = %v, want %v at offset %d", tc.writes, err, tc.err, tc.offset)
+		}
+		if _, err2 := w.Write([]byte("31467557")); err2 != err {
+			t.Errorf("NewDecodingWriter(%q) Write after error = %v, want %v", tc.writes, err2, err)
+		}
+	}
+
+	want := errors.New("sink failed")
+	w := NewDecodingWriter(errWriter{want})
+	if _, err := w.Write([]byte("31467557")); err != want {
+		t.Errorf("NewDecodingWriter Write to failing writer = %v, want %v", err, want)
+	}
+	if err := w.Close(); err != want {
+		t.Errorf("NewDecodingWriter Close after failed write = %v, want %v", err, want)
+	}
+}